@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/luneo7/rds-right-size/internal/cache"
+	"github.com/luneo7/rds-right-size/internal/cur"
+	"github.com/luneo7/rds-right-size/internal/currency"
 	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
 	"github.com/luneo7/rds-right-size/internal/generator"
 	rds "github.com/luneo7/rds-right-size/internal/rds-right-size"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
 	"github.com/luneo7/rds-right-size/internal/tui"
 	"github.com/luneo7/rds-right-size/internal/util"
 )
@@ -24,6 +31,18 @@ func main() {
 		runGenerateTypes()
 		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		runCache()
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "validate-types" {
+		runValidateTypes()
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff-types" {
+		runDiffTypes()
+		return
+	}
 
 	runAnalyze()
 }
@@ -43,6 +62,23 @@ func runAnalyze() {
 		cpuDownsize      float64
 		memUpsize        float64
 		preferNewGen     bool
+		riCoverage       bool
+		storageAnalysis  bool
+		includeOptimized bool
+		explain          bool
+		minConfidence    float64
+		minWindowDays    int
+		idleConnections  string
+		idle             = types.DefaultIdleThresholds()
+		curFile          string
+		pricingOverrides string
+		currencyCode     string
+		exchangeRates    string
+		pricingModel     string
+		accounts         string
+		orgUnit          string
+		roleName         string
+		maxConcurrency   int
 		tuiMode          bool
 	)
 
@@ -66,7 +102,43 @@ func runAnalyze() {
 	fs.StringVar(&statName, "s", "p99", "Statistic to be used to determine down/upsizing (shorthand)")
 	fs.BoolVar(&preferNewGen, "prefer-new-gen", false, "Prefer newer instance generation when scaling (e.g., r6g -> r7g)")
 	fs.BoolVar(&preferNewGen, "ng", false, "Prefer newer instance generation when scaling (shorthand)")
+	fs.BoolVar(&riCoverage, "ri-coverage", false, "Account for reserved instance coverage when estimating savings")
+	fs.BoolVar(&riCoverage, "ri", false, "Account for reserved instance coverage (shorthand)")
+	fs.BoolVar(&storageAnalysis, "storage-analysis", false, "Compare Aurora Standard and I/O-Optimized storage costs per cluster")
+	fs.BoolVar(&storageAnalysis, "sa", false, "Compare Aurora Standard and I/O-Optimized storage costs per cluster (shorthand)")
+	fs.BoolVar(&includeOptimized, "include-optimized", false, "Also report optimized instances (with their metrics) and instances skipped from analysis")
+	fs.BoolVar(&includeOptimized, "io", false, "Also report optimized and skipped instances (shorthand)")
+	fs.BoolVar(&explain, "explain", false, "Record in the JSON the candidate classes considered for each recommendation and why they were accepted or rejected")
+	fs.BoolVar(&explain, "ex", false, "Record the decision trace of each recommendation in the JSON (shorthand)")
+	fs.Float64Var(&minConfidence, "min-confidence", 0, "Minimum confidence score (0-100) of the recommendations to report")
+	fs.Float64Var(&minConfidence, "mcf", 0, "Minimum confidence score (0-100) to report (shorthand)")
+	fs.IntVar(&minWindowDays, "min-window", rds.DefaultMinWindowDays, "Minimum days of metrics after an instance class change before a recommendation is flagged")
+	fs.IntVar(&minWindowDays, "mw", rds.DefaultMinWindowDays, "Minimum days of metrics after an instance class change (shorthand)")
+	fs.StringVar(&idleConnections, "idle-connections", "1,2", "Comma separated connection counts held only by monitoring; instances at one of them with activity within the --idle-* thresholds are reported as LikelyIdle (empty disables)")
+	fs.StringVar(&idleConnections, "ic", "1,2", "Comma separated monitoring-only connection counts (shorthand)")
+	fs.Float64Var(&idle.CPU, "idle-cpu", idle.CPU, "CPU % at or below which a monitoring-only instance is likely idle")
+	fs.Float64Var(&idle.Queries, "idle-queries", idle.Queries, "Queries/second at or below which a monitoring-only instance is likely idle")
+	fs.Float64Var(&idle.CommitThroughput, "idle-commits", idle.CommitThroughput, "Commits/second at or below which a monitoring-only instance is likely idle")
+	fs.Float64Var(&idle.DMLThroughput, "idle-dml", idle.DMLThroughput, "DML statements/second at or below which a monitoring-only instance is likely idle")
+	fs.Float64Var(&idle.IOPS, "idle-iops", idle.IOPS, "Read plus write IOPS at or below which a monitoring-only instance is likely idle")
+	fs.StringVar(&curFile, "cur", "", "Cost and Usage Report export (.csv, .csv.gz or .parquet) to calibrate costs against billed rates")
+	fs.StringVar(&pricingOverrides, "pricing-overrides", "", "JSON file with private pricing (discount, family/class multipliers, absolute regional prices) layered on the instance types")
+	fs.StringVar(&pricingOverrides, "po", "", "JSON file with private pricing (shorthand)")
+	fs.StringVar(&currencyCode, "currency", currency.USD, "Currency to report costs in (ISO 4217 code, ex.: EUR); non-USD requires --exchange-rates")
+	fs.StringVar(&currencyCode, "ccy", currency.USD, "Currency to report costs in (shorthand)")
+	fs.StringVar(&exchangeRates, "exchange-rates", "", "Exchange rates per USD: rates JSON ({\"date\", \"rates\"}) or a static \"CODE RATE [DATE]\" table file")
+	fs.StringVar(&exchangeRates, "xr", "", "Exchange rates per USD (shorthand)")
+	fs.StringVar(&accounts, "accounts", "", "Comma separated account IDs to analyze by assuming --role-name in each")
+	fs.StringVar(&accounts, "a", "", "Comma separated account IDs to analyze (shorthand)")
+	fs.StringVar(&orgUnit, "ou", "", "AWS Organizations OU (or root) ID whose active accounts are analyzed by assuming --role-name")
+	fs.StringVar(&roleName, "role-name", "", "IAM role assumed in each account of --accounts/--ou")
+	fs.StringVar(&roleName, "rn", "", "IAM role assumed in each account (shorthand)")
+	fs.IntVar(&maxConcurrency, "max-concurrency", rds.DefaultMaxConcurrency, "Maximum number of account/region analyses run in parallel")
+	fs.IntVar(&maxConcurrency, "mc", rds.DefaultMaxConcurrency, "Maximum number of account/region analyses run in parallel (shorthand)")
+	fs.StringVar(&pricingModel, "pricing-model", string(types.OnDemand), "Pricing model used for costs (on-demand, ri-1y-no-upfront, ri-1y-partial-upfront, ri-1y-all-upfront, ri-3y-...)")
+	fs.StringVar(&pricingModel, "pm", string(types.OnDemand), "Pricing model used for costs (shorthand)")
 	fs.BoolVar(&tuiMode, "tui", false, "Launch interactive TUI mode")
+	cacheOpts := registerCacheFlags(fs)
 
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		os.Exit(2)
@@ -77,21 +149,101 @@ func runAnalyze() {
 		os.Exit(2)
 	}
 
+	model, err := types.ParsePricingModel(pricingModel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	idle.MonitoringConnections, err = util.SplitInts(idleConnections)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --idle-connections: %v\n", err)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if minWindowDays <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --min-window must be at least 1 day\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if minConfidence < 0 || minConfidence > 100 {
+		fmt.Fprintf(os.Stderr, "Error: --min-confidence must be between 0 and 100\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var overrides *types.PricingOverrides
+	if pricingOverrides != "" {
+		overrides, err = rds.LoadPricingOverrides(pricingOverrides)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if curFile != "" {
+		if err := rds.CheckCURCalibration(model, overrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --cur: %v\n", err)
+			fs.Usage()
+			os.Exit(2)
+		}
+	}
+
+	var curCosts cur.Costs
+	if curFile != "" && !tuiMode {
+		curCosts, err = cur.Load(curFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	accountIds := util.SplitList(accounts)
+	if (len(accountIds) > 0 || orgUnit != "") && roleName == "" {
+		fmt.Fprintf(os.Stderr, "Error: --role-name is required with --accounts or --ou\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	reportCurrency, err := currency.Load(currencyCode, exchangeRates)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	downloadCache := cacheOpts.open()
+
 	if tuiMode {
 		defaults := tui.ConfigValues{
-			Profile:          profile,
-			Region:           region,
-			Tags:             tags,
-			Period:           period,
-			CPUUpsize:        cpuUpsize,
-			CPUDownsize:      cpuDownsize,
-			MemUpsize:        memUpsize,
-			Stat:             statName,
-			PreferNewGen:     preferNewGen,
-			InstanceTypesURL: instanceTypesUrl,
-		}
-
-		if err := tui.Run(defaults); err != nil {
+			Profile:              profile,
+			Region:               region,
+			Tags:                 tags,
+			Period:               period,
+			CPUUpsize:            cpuUpsize,
+			CPUDownsize:          cpuDownsize,
+			MemUpsize:            memUpsize,
+			Stat:                 statName,
+			PreferNewGen:         preferNewGen,
+			RICoverage:           riCoverage,
+			StorageAnalysis:      storageAnalysis,
+			IncludeOptimized:     includeOptimized,
+			MinConfidence:        minConfidence,
+			MinWindowDays:        minWindowDays,
+			IdleThresholds:       &idle,
+			PricingModel:         string(model),
+			CURFile:              curFile,
+			PricingOverridesFile: pricingOverrides,
+			Currency:             currencyCode,
+			ExchangeRatesFile:    exchangeRates,
+			Accounts:             joinAccounts(accountIds, orgUnit),
+			RoleName:             roleName,
+			InstanceTypesURL:     instanceTypesUrl,
+		}
+
+		if err := tui.Run(defaults, downloadCache); err != nil {
 			fmt.Printf("TUI error: %v\n", err)
 			os.Exit(1)
 		}
@@ -101,7 +253,7 @@ func runAnalyze() {
 	// Original CLI behavior
 	regions := util.SplitRegions(region)
 
-	if len(regions) <= 1 {
+	if len(regions) <= 1 && len(accountIds) == 0 && orgUnit == "" {
 		// Single region — existing behavior
 		var optFns []func(*config.LoadOptions) error
 
@@ -120,7 +272,26 @@ func runAnalyze() {
 			os.Exit(1)
 		}
 
-		err = rds.NewRDSRightSize(&instanceTypesUrl, &cfg, period, util.ParseTags(tags), cpuDownsize, cpuUpsize, memUpsize, cwTypes.StatName(statName), preferNewGen, region).DoAnalyzeRDS()
+		instanceTypes, err := rds.LoadInstanceTypes(instanceTypesUrl, overrides, downloadCache)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, warning := range instanceTypes.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: instance types: %s\n", warning)
+		}
+
+		err = rds.NewRDSRightSize(instanceTypes, instanceTypesUrl, &cfg, period, util.ParseTags(tags), cpuDownsize, cpuUpsize, memUpsize, cwTypes.StatName(statName), preferNewGen, region, model, overrides).DoAnalyzeRDS(&rds.AnalysisOptions{
+			ReservedCoverage: riCoverage,
+			StorageAnalysis:  storageAnalysis,
+			IncludeOptimized: includeOptimized,
+			Explain:          explain,
+			MinConfidence:    minConfidence,
+			MinWindowDays:    minWindowDays,
+			IdleThresholds:   &idle,
+			CURCosts:         curCosts,
+			Currency:         reportCurrency,
+			Cache:            downloadCache,
+		})
 
 		if err != nil {
 			log.Fatal(err)
@@ -130,19 +301,38 @@ func runAnalyze() {
 
 	// Multi-region parallel analysis
 	allRecs, _, err := rds.AnalyzeMultiRegion(context.Background(), rds.MultiRegionOptions{
-		Regions:          regions,
-		Profile:          profile,
-		InstanceTypesURL: instanceTypesUrl,
-		Period:           period,
-		Tags:             util.ParseTags(tags),
-		CPUDownsize:      cpuDownsize,
-		CPUUpsize:        cpuUpsize,
-		MemUpsize:        memUpsize,
-		Stat:             cwTypes.StatName(statName),
-		PreferNewGen:     preferNewGen,
+		Regions:            regions,
+		Profile:            profile,
+		InstanceTypesURL:   instanceTypesUrl,
+		Period:             period,
+		Tags:               util.ParseTags(tags),
+		CPUDownsize:        cpuDownsize,
+		CPUUpsize:          cpuUpsize,
+		MemUpsize:          memUpsize,
+		Stat:               cwTypes.StatName(statName),
+		PreferNewGen:       preferNewGen,
+		PricingModel:       model,
+		PricingOverrides:   overrides,
+		ReservedCoverage:   riCoverage,
+		StorageAnalysis:    storageAnalysis,
+		IncludeOptimized:   includeOptimized,
+		Explain:            explain,
+		MinConfidence:      minConfidence,
+		MinWindowDays:      minWindowDays,
+		IdleThresholds:     &idle,
+		CURCosts:           curCosts,
+		Currency:           reportCurrency,
+		Cache:              downloadCache,
+		Accounts:           accountIds,
+		OrganizationalUnit: orgUnit,
+		RoleName:           roleName,
+		MaxConcurrency:     maxConcurrency,
 		OnWarning: func(instanceLabel, msg string) {
 			fmt.Fprintf(os.Stderr, "Warning: skipping instance %s: %s\n", instanceLabel, msg)
 		},
+		OnInstanceTypesWarning: func(msg string) {
+			fmt.Fprintf(os.Stderr, "Warning: instance types: %s\n", msg)
+		},
 		OnRegionError: func(region string, err error) {
 			fmt.Fprintf(os.Stderr, "Warning: %s analysis failed: %v\n", region, err)
 		},
@@ -156,6 +346,14 @@ func runAnalyze() {
 	}
 }
 
+// joinAccounts renders account IDs and an OU ID as the TUI's single accounts field.
+func joinAccounts(accountIds []string, orgUnit string) string {
+	if orgUnit != "" {
+		accountIds = append(accountIds, orgUnit)
+	}
+	return strings.Join(accountIds, ",")
+}
+
 // runGenerateTypes handles the generate-types subcommand.
 func runGenerateTypes() {
 	fs := flag.NewFlagSet("generate-types", flag.ExitOnError)
@@ -166,6 +364,11 @@ func runGenerateTypes() {
 		profile       string
 		output        string
 		targetRegions string
+		pricingDir    string
+		orderableFile string
+		downloadOnly  bool
+		eolFile       string
+		legacyFormat  bool
 	)
 
 	fs.StringVar(&engine, "engine", "both", "Database engine (both, aurora-mysql, or aurora-postgresql)")
@@ -178,6 +381,14 @@ func runGenerateTypes() {
 	fs.StringVar(&output, "o", "aurora_instance_types.json", "Output file path (shorthand)")
 	fs.StringVar(&targetRegions, "target-regions", "all", "Target regions for pricing/availability (comma-separated or 'all')")
 	fs.StringVar(&targetRegions, "tr", "all", "Target regions (shorthand)")
+	fs.StringVar(&pricingDir, "pricing-dir", "", "Directory of bulk pricing files (<region>/index.json) to generate from instead of downloading; with --download-only, where they are saved")
+	fs.StringVar(&pricingDir, "pd", "", "Directory of bulk pricing files (shorthand)")
+	fs.StringVar(&orderableFile, "orderable-file", "", "Saved DescribeOrderableDBInstanceOptions JSON (comma-separated for several) used instead of the API (default: <pricing-dir>/"+generator.OrderableFileName+" if present)")
+	fs.StringVar(&orderableFile, "of", "", "Saved DescribeOrderableDBInstanceOptions JSON (shorthand)")
+	fs.BoolVar(&downloadOnly, "download-only", false, "Only download the bulk pricing files and orderable options into --pricing-dir for later offline generation")
+	fs.StringVar(&eolFile, "eol-file", "", "JSON object of instance families or classes to end-of-support dates (the only source of dates), added to the built-in end-of-life families")
+	fs.BoolVar(&legacyFormat, "legacy-format", false, "Write the legacy bare instance types map with a storage pricing sidecar, for older rds-right-size versions")
+	cacheOpts := registerCacheFlags(fs)
 
 	// Parse from os.Args[2:] since os.Args[1] is "generate-types"
 	if err := fs.Parse(os.Args[2:]); err != nil {
@@ -196,6 +407,12 @@ func runGenerateTypes() {
 		os.Exit(2)
 	}
 
+	if downloadOnly && pricingDir == "" {
+		fmt.Fprintf(os.Stderr, "Error: --download-only requires --pricing-dir\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
 	// Build AWS config
 	var optFns []func(*config.LoadOptions) error
 	if profile != "" {
@@ -216,13 +433,361 @@ func runGenerateTypes() {
 		Region:        region,
 		TargetRegions: targetRegions,
 		Output:        output,
+		Cache:         cacheOpts.open(),
+		PricingDir:    pricingDir,
+		OrderableFile: orderableFile,
+		EOLFile:       eolFile,
+		LegacyFormat:  legacyFormat,
 		OnStatus: func(status string) {
 			fmt.Println(status)
 		},
 	}
 
-	if err := generator.GenerateInstanceTypes(context.Background(), cfg, opts); err != nil {
+	generate := generator.GenerateInstanceTypes
+	if downloadOnly {
+		generate = generator.DownloadInputs
+	}
+	if err := generate(context.Background(), cfg, opts); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runValidateTypes handles the validate-types subcommand: it checks an instance
+// types file and reports every issue, exiting with 1 on errors (or warnings with --strict).
+func runValidateTypes() {
+	fs := flag.NewFlagSet("validate-types", flag.ExitOnError)
+
+	var (
+		instanceTypesUrl string
+		format           string
+		strict           bool
+	)
+
+	fs.StringVar(&instanceTypesUrl, "instance-types", defaultInstanceTypesURL, "Instance types JSON URL or local file path (or pass it as the argument)")
+	fs.StringVar(&instanceTypesUrl, "i", defaultInstanceTypesURL, "Instance types JSON URL or local file path (shorthand)")
+	fs.StringVar(&format, "format", "text", "Output format (text or json)")
+	fs.StringVar(&format, "f", "text", "Output format (shorthand)")
+	fs.BoolVar(&strict, "strict", false, "Also fail on warnings")
+	cacheOpts := registerCacheFlags(fs)
+
+	// Parse from os.Args[2:] since os.Args[1] is "validate-types"
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		os.Exit(2)
+	}
+	// Allow flags after the file argument
+	if fs.NArg() > 0 {
+		instanceTypesUrl = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			os.Exit(2)
+		}
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: validate-types takes a single instance types file\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be 'text' or 'json'\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	file, err := rds.ReadInstanceTypes(instanceTypesUrl, cacheOpts.open())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	instanceTypes := file.InstanceTypes
+
+	issues := rds.ValidateInstanceTypes(instanceTypes)
+	errorCount := len(rds.ValidationErrors(issues))
+	warningCount := len(issues) - errorCount
+
+	if format == "json" {
+		report := struct {
+			Source        string                `json:"source"`
+			SchemaVersion int                   `json:"schemaVersion"`
+			InstanceTypes int                   `json:"instanceTypes"`
+			Valid         bool                  `json:"valid"`
+			Errors        int                   `json:"errors"`
+			Warnings      int                   `json:"warnings"`
+			Issues        []rds.ValidationIssue `json:"issues"`
+		}{
+			Source:        instanceTypesUrl,
+			SchemaVersion: file.SchemaVersion,
+			InstanceTypes: len(instanceTypes),
+			Valid:         errorCount == 0,
+			Errors:        errorCount,
+			Warnings:      warningCount,
+			Issues:        issues,
+		}
+		if report.Issues == nil {
+			report.Issues = []rds.ValidationIssue{}
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, issue := range issues {
+			fmt.Printf("%-7s  %-16s  %s\n", issue.Severity, issue.Check, issue)
+		}
+		fmt.Printf("%s: schema v%d, %d instance types, %d errors, %d warnings\n", instanceTypesUrl, file.SchemaVersion, len(instanceTypes), errorCount, warningCount)
+	}
+
+	if errorCount > 0 || (strict && warningCount > 0) {
+		os.Exit(1)
+	}
+}
+
+// runDiffTypes handles the diff-types subcommand: it compares two instance types
+// files (or URLs) and reports what changed.
+func runDiffTypes() {
+	fs := flag.NewFlagSet("diff-types", flag.ExitOnError)
+
+	var (
+		format         string
+		minPriceChange float64
+		exitCode       bool
+		currencyCode   string
+		exchangeRates  string
+	)
+
+	fs.StringVar(&format, "format", "text", "Output format (text or json)")
+	fs.StringVar(&format, "f", "text", "Output format (shorthand)")
+	fs.Float64Var(&minPriceChange, "min-price-change", 1, "Minimum price change % to report")
+	fs.Float64Var(&minPriceChange, "mpc", 1, "Minimum price change % to report (shorthand)")
+	fs.BoolVar(&exitCode, "exit-code", false, "Exit with status 1 when there are differences")
+	fs.StringVar(&currencyCode, "currency", currency.USD, "Currency to report prices in (ISO 4217 code, ex.: EUR); non-USD requires --exchange-rates")
+	fs.StringVar(&currencyCode, "ccy", currency.USD, "Currency to report prices in (shorthand)")
+	fs.StringVar(&exchangeRates, "exchange-rates", "", "Exchange rates per USD: rates JSON ({\"date\", \"rates\"}) or a static \"CODE RATE [DATE]\" table file")
+	fs.StringVar(&exchangeRates, "xr", "", "Exchange rates per USD (shorthand)")
+	cacheOpts := registerCacheFlags(fs)
+
+	// Parse from os.Args[2:] since os.Args[1] is "diff-types", allowing flags
+	// between and after the two files
+	var files []string
+	args := os.Args[2:]
+	for {
+		if err := fs.Parse(args); err != nil {
+			os.Exit(2)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		files = append(files, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(files) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: rds-right-size diff-types [flags] <old.json> <new.json>\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be 'text' or 'json'\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	reportCurrency, err := currency.Load(currencyCode, exchangeRates)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	downloadCache := cacheOpts.open()
+	oldFile, err := rds.ReadInstanceTypes(files[0], downloadCache)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	newFile, err := rds.ReadInstanceTypes(files[1], downloadCache)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	diff := rds.DiffInstanceTypes(oldFile.InstanceTypes, newFile.InstanceTypes, minPriceChange)
+	diff.Old = &types.InstanceTypesProvenance{Source: files[0], SchemaVersion: oldFile.SchemaVersion, Metadata: oldFile.Metadata}
+	diff.New = &types.InstanceTypesProvenance{Source: files[1], SchemaVersion: newFile.SchemaVersion, Metadata: newFile.Metadata}
+	diff.ConvertPrices(reportCurrency)
+
+	if format == "json" {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else if err := rds.WriteInstanceTypesDiff(os.Stdout, diff); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if exitCode && !diff.Empty() {
+		os.Exit(1)
+	}
+}
+
+// cacheFlags holds the download cache flags shared by the analyze, generate-types
+// and cache subcommands.
+type cacheFlags struct {
+	dir     string
+	ttl     time.Duration
+	offline bool
+}
+
+func registerCacheFlags(fs *flag.FlagSet) *cacheFlags {
+	f := &cacheFlags{}
+	fs.StringVar(&f.dir, "cache-dir", "", "Directory for cached instance types and pricing downloads (default: user cache dir/rds-right-size)")
+	fs.DurationVar(&f.ttl, "cache-ttl", cache.DefaultTTL, "How long cached downloads are used before revalidating with the server (ex.: 6h, 0 to always revalidate)")
+	fs.BoolVar(&f.offline, "offline", false, "Use only cached downloads, never contacting the network")
+	return f
+}
+
+// open creates the download cache. Without a usable cache directory downloads go
+// uncached, unless --offline was given, which exits.
+func (f *cacheFlags) open() *cache.Cache {
+	c, err := cache.New(f.dir, f.ttl, f.offline)
+	if err != nil {
+		if f.offline {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %v; downloading without cache\n", err)
+		return nil
+	}
+	return c
+}
+
+// runCache handles the cache subcommand: list, prune or warm the download cache.
+func runCache() {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "Usage: rds-right-size cache <list|prune|warm> [flags]\n")
+	}
+	if len(os.Args) < 3 {
+		usage()
+		os.Exit(2)
+	}
+
+	action := os.Args[2]
+	fs := flag.NewFlagSet("cache "+action, flag.ExitOnError)
+	cacheOpts := registerCacheFlags(fs)
+
+	var (
+		olderThan        time.Duration
+		targetRegions    string
+		instanceTypesUrl string
+	)
+	switch action {
+	case "list":
+	case "prune":
+		fs.DurationVar(&olderThan, "older-than", 0, "Only remove entries fetched longer ago than this (ex.: 720h); 0 removes everything")
+	case "warm":
+		fs.StringVar(&targetRegions, "target-regions", "all", "Regions whose bulk pricing files are cached (comma-separated, 'all', or 'none')")
+		fs.StringVar(&targetRegions, "tr", "all", "Target regions (shorthand)")
+		fs.StringVar(&instanceTypesUrl, "instance-types", defaultInstanceTypesURL, "Instance types JSON URL to cache along with its storage pricing file")
+		fs.StringVar(&instanceTypesUrl, "i", defaultInstanceTypesURL, "Instance types JSON URL to cache (shorthand)")
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown cache action %q\n", action)
+		usage()
+		os.Exit(2)
+	}
+
+	// Parse from os.Args[3:] since os.Args[1:3] is "cache <action>"
+	if err := fs.Parse(os.Args[3:]); err != nil {
+		os.Exit(2)
+	}
+
+	c, err := cache.New(cacheOpts.dir, cacheOpts.ttl, cacheOpts.offline)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch action {
+	case "list":
+		entries, err := c.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Cache directory: %s\n", c.Dir)
+		for _, entry := range entries {
+			state := "fresh"
+			if time.Since(entry.FetchedAt) >= c.TTL {
+				state = "stale"
+			}
+			fmt.Printf("%s  %10s  %-5s  %s\n", entry.FetchedAt.Local().Format(time.DateTime), formatBytes(entry.Size), state, entry.URL)
+		}
+		fmt.Printf("%d entries\n", len(entries))
+
+	case "prune":
+		removed, freed, err := c.Prune(olderThan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %d entries (%s) from %s\n", removed, formatBytes(freed), c.Dir)
+
+	case "warm":
+		ctx := context.Background()
+		var urls []string
+		if strings.HasPrefix(instanceTypesUrl, "http://") || strings.HasPrefix(instanceTypesUrl, "https://") {
+			urls = append(urls, instanceTypesUrl)
+		}
+		if targetRegions != "none" {
+			regions := util.SplitList(targetRegions)
+			if targetRegions == "all" {
+				regions, err = generator.FetchRegionList(ctx, c)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			for _, rgn := range regions {
+				urls = append(urls, generator.BulkPricingURL(rgn))
+			}
+		}
+
+		failed := 0
+		for i, url := range urls {
+			fmt.Printf("[%d/%d] %s\n", i+1, len(urls), url)
+			if _, err := c.Fetch(ctx, url); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				failed++
+			}
+		}
+
+		// The storage pricing sidecar is optional (only --storage-analysis of legacy files needs it)
+		if len(urls) > 0 && urls[0] == instanceTypesUrl {
+			storageUrl := types.StoragePricingPath(instanceTypesUrl)
+			if _, err := c.Fetch(ctx, storageUrl); err != nil {
+				fmt.Fprintf(os.Stderr, "Note: storage pricing not cached: %v\n", err)
+			} else {
+				fmt.Printf("Cached %s\n", storageUrl)
+			}
+		}
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "%d of %d downloads failed\n", failed, len(urls))
+			os.Exit(1)
+		}
+	}
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "312.4 MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
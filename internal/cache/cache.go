@@ -0,0 +1,273 @@
+// Package cache keeps downloaded instance types and pricing files on disk so
+// repeated runs revalidate them (ETag / Last-Modified) instead of re-downloading.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultTTL is how long a cached file is used without revalidation.
+const DefaultTTL = 24 * time.Hour
+
+// ErrNotCached is returned in offline mode for URLs without a cached copy.
+var ErrNotCached = errors.New("not in cache")
+
+// Cache is an on-disk HTTP download cache. A nil *Cache downloads without caching.
+type Cache struct {
+	// Dir holds one "<key>.body" file per entry and its "<key>.json" metadata.
+	Dir string
+	// TTL is how long an entry is served without contacting the server. Zero
+	// revalidates on every use.
+	TTL time.Duration
+	// Offline serves only cached entries, never contacting the server.
+	Offline bool
+	// Client performs downloads. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Entry is the metadata of one cached download.
+type Entry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	Size         int64     `json:"size"`
+}
+
+// New returns a cache rooted at dir, or at DefaultDir when dir is empty.
+func New(dir string, ttl time.Duration, offline bool) (*Cache, error) {
+	if dir == "" {
+		var err error
+		dir, err = DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+	return &Cache{Dir: dir, TTL: ttl, Offline: offline}, nil
+}
+
+// DefaultDir returns the per-user cache directory (e.g. ~/.cache/rds-right-size).
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(base, "rds-right-size"), nil
+}
+
+// Open returns the content of url, from the cache when fresh, after revalidating
+// when stale, or downloaded otherwise. When revalidation fails with a network
+// error the stale copy is served. The caller must close the reader.
+func (c *Cache) Open(ctx context.Context, url string) (io.ReadCloser, error) {
+	if c == nil {
+		resp, err := c.get(ctx, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("download of %s returned HTTP %d", url, resp.StatusCode)
+		}
+		return resp.Body, nil
+	}
+
+	path, err := c.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Fetch makes sure url is cached and fresh, and returns the path of its content.
+func (c *Cache) Fetch(ctx context.Context, url string) (string, error) {
+	key := cacheKey(url)
+	bodyPath := filepath.Join(c.Dir, key+".body")
+	entry, cached := c.readEntry(key)
+
+	if cached {
+		if c.Offline || time.Since(entry.FetchedAt) < c.TTL {
+			return bodyPath, nil
+		}
+	} else if c.Offline {
+		return "", fmt.Errorf("%s: %w (run without --offline or warm the cache first)", url, ErrNotCached)
+	}
+
+	headers := map[string]string{}
+	if cached {
+		if entry.ETag != "" {
+			headers["If-None-Match"] = entry.ETag
+		}
+		if entry.LastModified != "" {
+			headers["If-Modified-Since"] = entry.LastModified
+		}
+	}
+
+	resp, err := c.get(ctx, url, headers)
+	if err != nil {
+		if cached && ctx.Err() == nil {
+			return bodyPath, nil
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		entry.FetchedAt = time.Now()
+		if err := c.writeEntry(key, entry); err != nil {
+			return "", err
+		}
+		return bodyPath, nil
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("download of %s returned HTTP %d", url, resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create cache file: %w", err)
+	}
+	size, copyErr := io.Copy(tmp, resp.Body)
+	closeErr := tmp.Close()
+	if copyErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		if copyErr != nil {
+			return "", fmt.Errorf("failed to download %s: %w", url, copyErr)
+		}
+		return "", fmt.Errorf("failed to write cache file: %w", closeErr)
+	}
+	if err := os.Rename(tmp.Name(), bodyPath); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to store cache file: %w", err)
+	}
+
+	entry = Entry{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+		Size:         size,
+	}
+	if err := c.writeEntry(key, entry); err != nil {
+		return "", err
+	}
+	return bodyPath, nil
+}
+
+// List returns all cached entries, most recently fetched first.
+func (c *Cache) List() ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(c.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		entry, ok := c.readEntry(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FetchedAt.After(entries[j].FetchedAt)
+	})
+	return entries, nil
+}
+
+// Prune removes entries fetched longer than olderThan ago (all entries when
+// olderThan is zero) along with leftover partial downloads, and returns how many
+// entries and bytes were removed.
+func (c *Cache) Prune(olderThan time.Duration) (int, int64, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	removed := 0
+	var freed int64
+	for _, entry := range entries {
+		if olderThan > 0 && time.Since(entry.FetchedAt) < olderThan {
+			continue
+		}
+		key := cacheKey(entry.URL)
+		if err := os.Remove(filepath.Join(c.Dir, key+".body")); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, freed, err
+		}
+		if err := os.Remove(filepath.Join(c.Dir, key+".json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, freed, err
+		}
+		removed++
+		freed += entry.Size
+	}
+
+	partials, _ := filepath.Glob(filepath.Join(c.Dir, "*.tmp"))
+	for _, partial := range partials {
+		_ = os.Remove(partial)
+	}
+	return removed, freed, nil
+}
+
+func (c *Cache) get(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := http.DefaultClient
+	if c != nil && c.Client != nil {
+		client = c.Client
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return resp, nil
+}
+
+func (c *Cache) readEntry(key string) (Entry, bool) {
+	var entry Entry
+	data, err := os.ReadFile(filepath.Join(c.Dir, key+".json"))
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+	if _, err := os.Stat(filepath.Join(c.Dir, key+".body")); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+func (c *Cache) writeEntry(key string, entry Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(c.Dir, key+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache metadata: %w", err)
+	}
+	return nil
+}
+
+func cacheKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:16])
+}
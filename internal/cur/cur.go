@@ -0,0 +1,268 @@
+// Package cur reads AWS Cost and Usage Report exports and derives the effective
+// cost of each RDS instance, so list-price estimates can be calibrated against
+// what is actually billed (EDP discounts, credits, reservations).
+package cur
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Normalized column names (CUR 2.0 / Parquet style). Legacy CSV headers such as
+// "lineItem/ResourceId" are normalized to the same form.
+const (
+	colResourceId          = "line_item_resource_id"
+	colProductCode         = "line_item_product_code"
+	colUsageType           = "line_item_usage_type"
+	colLineItemType        = "line_item_line_item_type"
+	colUsageAmount         = "line_item_usage_amount"
+	colUnblendedCost       = "line_item_unblended_cost"
+	colNetUnblendedCost    = "line_item_net_unblended_cost"
+	colPublicOnDemandCost  = "pricing_public_on_demand_cost"
+	colReservationEffCost  = "reservation_effective_cost"
+	colSavingsPlanEffCost  = "savings_plan_savings_plan_effective_cost"
+	rdsProductCode         = "AmazonRDS"
+	instanceUsageTypeToken = "InstanceUsage"
+)
+
+// InstanceCost is the billed and public on-demand cost of one RDS instance over
+// the report period.
+type InstanceCost struct {
+	// Hours of instance usage in the report.
+	UsageHours float64
+	// EffectiveCost is what was actually paid: usage after discounts, the
+	// amortized reservation or savings plan cost, plus credits and discounts.
+	EffectiveCost float64
+	// PublicOnDemandCost is the same usage at public on-demand rates.
+	PublicOnDemandCost float64
+}
+
+// HourlyRate returns the effective hourly rate, or 0 without usage.
+func (c InstanceCost) HourlyRate() float64 {
+	if c.UsageHours <= 0 {
+		return 0
+	}
+	return c.EffectiveCost / c.UsageHours
+}
+
+// DiscountRatio returns effective cost over public on-demand cost (e.g. 0.82 for
+// an 18% effective discount), or 0 when there is no on-demand cost to compare.
+func (c InstanceCost) DiscountRatio() float64 {
+	if c.PublicOnDemandCost <= 0 {
+		return 0
+	}
+	return c.EffectiveCost / c.PublicOnDemandCost
+}
+
+// Costs maps DB instance ARNs to their costs.
+type Costs map[string]InstanceCost
+
+// Load parses a CUR export (.csv, .csv.gz or .parquet, chosen by file extension)
+// and aggregates RDS instance usage line items by resource ARN.
+func Load(path string) (Costs, error) {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".parquet"):
+		return loadParquet(path)
+	case strings.HasSuffix(lower, ".csv"), strings.HasSuffix(lower, ".csv.gz"):
+		return loadCSV(path)
+	default:
+		return nil, fmt.Errorf("unsupported CUR file %s (expected .csv, .csv.gz or .parquet)", path)
+	}
+}
+
+// NormalizeColumn maps legacy CUR headers ("lineItem/UnblendedCost") and CUR 2.0
+// names ("line_item_unblended_cost") to the same snake_case form.
+func NormalizeColumn(name string) string {
+	var b strings.Builder
+	prevLower := false
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r == '/' || r == '.' || r == ' ' || r == '-':
+			b.WriteByte('_')
+			prevLower = false
+		case unicode.IsUpper(r):
+			if prevLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			prevLower = false
+		default:
+			b.WriteRune(r)
+			prevLower = unicode.IsLower(r) || unicode.IsDigit(r)
+		}
+	}
+	return b.String()
+}
+
+// accumulator sums line items into per-instance costs.
+type accumulator struct {
+	costs Costs
+}
+
+// add records one line item given its column values by normalized name.
+func (a *accumulator) add(get func(column string) string) {
+	resourceId := get(colResourceId)
+	if !strings.HasPrefix(resourceId, "arn:") || !strings.Contains(resourceId, ":rds:") {
+		return
+	}
+	if productCode := get(colProductCode); productCode != "" && productCode != rdsProductCode {
+		return
+	}
+	if !strings.Contains(get(colUsageType), instanceUsageTypeToken) {
+		return
+	}
+
+	number := func(column string) float64 {
+		v, err := strconv.ParseFloat(get(column), 64)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+
+	cost := a.costs[resourceId]
+	switch get(colLineItemType) {
+	case "Usage":
+		cost.UsageHours += number(colUsageAmount)
+		cost.PublicOnDemandCost += number(colPublicOnDemandCost)
+		if get(colNetUnblendedCost) != "" {
+			cost.EffectiveCost += number(colNetUnblendedCost)
+		} else {
+			cost.EffectiveCost += number(colUnblendedCost)
+		}
+	case "DiscountedUsage":
+		cost.UsageHours += number(colUsageAmount)
+		cost.PublicOnDemandCost += number(colPublicOnDemandCost)
+		cost.EffectiveCost += number(colReservationEffCost)
+	case "SavingsPlanCoveredUsage":
+		cost.UsageHours += number(colUsageAmount)
+		cost.PublicOnDemandCost += number(colPublicOnDemandCost)
+		cost.EffectiveCost += number(colSavingsPlanEffCost)
+	case "Credit", "Refund", "EdpDiscount", "BundledDiscount", "PrivateRateDiscount", "Discount":
+		cost.EffectiveCost += number(colUnblendedCost)
+	default:
+		return
+	}
+	a.costs[resourceId] = cost
+}
+
+func loadCSV(path string) (Costs, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CUR file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var input io.Reader = f
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress CUR file %s: %w", path, err)
+		}
+		defer gz.Close()
+		input = gz
+	}
+
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CUR header from %s: %w", path, err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[NormalizeColumn(name)] = i
+	}
+	if _, ok := index[colResourceId]; !ok {
+		return nil, fmt.Errorf("CUR file %s has no resource ID column (enable \"Include resource IDs\" on the report)", path)
+	}
+
+	acc := accumulator{costs: make(Costs)}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CUR file %s: %w", path, err)
+		}
+		acc.add(func(column string) string {
+			if i, ok := index[column]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		})
+	}
+	return acc.costs, nil
+}
+
+func loadParquet(path string) (Costs, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CUR file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat CUR file %s: %w", path, err)
+	}
+	file, err := parquet.OpenFile(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Parquet CUR file %s: %w", path, err)
+	}
+
+	// Only top-level leaf columns are needed; nested map columns (tags, product) are skipped
+	index := make(map[string]int)
+	for i, columnPath := range file.Schema().Columns() {
+		if len(columnPath) == 1 {
+			index[NormalizeColumn(columnPath[0])] = i
+		}
+	}
+	if _, ok := index[colResourceId]; !ok {
+		return nil, fmt.Errorf("CUR file %s has no resource ID column (enable \"Include resource IDs\" on the report)", path)
+	}
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	acc := accumulator{costs: make(Costs)}
+	values := make(map[int]string, len(index))
+	rows := make([]parquet.Row, 256)
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			clear(values)
+			for _, v := range row {
+				if !v.IsNull() {
+					values[v.Column()] = v.String()
+				}
+			}
+			acc.add(func(column string) string {
+				if i, ok := index[column]; ok {
+					return values[i]
+				}
+				return ""
+			})
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Parquet CUR file %s: %w", path, err)
+		}
+	}
+	return acc.costs, nil
+}
@@ -0,0 +1,238 @@
+// Package currency converts USD prices to a reporting currency and formats
+// amounts with the currency's symbol.
+package currency
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// USD is the currency all prices are computed in.
+const USD = "USD"
+
+// symbols maps ISO 4217 codes to display symbols. Codes without an entry are
+// printed as a "CODE " prefix.
+var symbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "CN¥",
+	"INR": "₹",
+	"KRW": "₩",
+	"BRL": "R$",
+	"CAD": "CA$",
+	"AUD": "A$",
+	"NZD": "NZ$",
+	"HKD": "HK$",
+	"SGD": "S$",
+	"MXN": "MX$",
+	"CHF": "CHF ",
+	"SEK": "SEK ",
+	"NOK": "NOK ",
+	"DKK": "DKK ",
+	"PLN": "zł",
+	"ZAR": "R",
+	"ILS": "₪",
+}
+
+// zeroDecimal lists currencies without minor units.
+var zeroDecimal = map[string]bool{
+	"JPY": true,
+	"KRW": true,
+}
+
+// Currency is a reporting currency and the exchange rate used to convert USD
+// prices into it. A nil *Currency means USD.
+type Currency struct {
+	Code string
+	// Rate is the number of Code units per USD.
+	Rate float64
+	// Date is the date the rate applies to, as given by the rate source.
+	Date string `json:"Date,omitempty"`
+	// Source is the file the rate was read from.
+	Source string `json:"Source,omitempty"`
+}
+
+// Convert converts a USD amount to the currency.
+func (c *Currency) Convert(usd float64) float64 {
+	if c == nil {
+		return usd
+	}
+	return usd * c.Rate
+}
+
+// Symbol returns the display symbol of the currency.
+func (c *Currency) Symbol() string {
+	code := USD
+	if c != nil {
+		code = c.Code
+	}
+	if symbol, ok := symbols[code]; ok {
+		return symbol
+	}
+	return code + " "
+}
+
+// Format formats an amount already in the currency with its symbol and usual
+// precision, e.g. "€1234.50" or "-¥1200".
+func (c *Currency) Format(amount float64) string {
+	return c.format(amount, 0)
+}
+
+// FormatRate formats an hourly rate with two extra digits of precision.
+func (c *Currency) FormatRate(amount float64) string {
+	return c.format(amount, 2)
+}
+
+func (c *Currency) format(amount float64, extraDecimals int) string {
+	decimals := 2
+	if c != nil && zeroDecimal[c.Code] {
+		decimals = 0
+	}
+	decimals += extraDecimals
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = math.Abs(amount)
+	}
+	return sign + c.Symbol() + strconv.FormatFloat(amount, 'f', decimals, 64)
+}
+
+// Label describes the conversion for output metadata, e.g.
+// "EUR (0.9200 per USD as of 2026-10-01)". Returns "" for USD.
+func (c *Currency) Label() string {
+	if c == nil || c.Code == USD {
+		return ""
+	}
+	label := fmt.Sprintf("%s (%.4f per USD", c.Code, c.Rate)
+	if c.Date != "" {
+		label += " as of " + c.Date
+	}
+	return label + ")"
+}
+
+// Load returns the currency for code with its rate read from source. USD needs no
+// source and yields nil. Sources ending in .json are read as a rates document:
+//
+//	{"base": "USD", "date": "2026-10-01", "rates": {"EUR": 0.92, "GBP": 0.79}}
+//
+// where "timestamp" (Unix seconds) may stand in for "date" and a non-USD base is
+// cross-converted through its USD rate. Any other file is a static table with one
+// "CODE RATE [DATE]" entry per line (comma or whitespace separated, "#" comments),
+// rates given per USD.
+func Load(code, source string) (*Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" || code == USD {
+		return nil, nil
+	}
+	if source == "" {
+		return nil, fmt.Errorf("currency %s requires an exchange rate source", code)
+	}
+
+	var (
+		c   *Currency
+		err error
+	)
+	if strings.HasSuffix(strings.ToLower(source), ".json") {
+		c, err = loadRatesJSON(code, source)
+	} else {
+		c, err = loadRatesTable(code, source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.Rate <= 0 {
+		return nil, fmt.Errorf("exchange rate for %s in %s must be positive", code, source)
+	}
+	c.Source = source
+	return c, nil
+}
+
+type ratesDocument struct {
+	Base      string             `json:"base"`
+	Date      string             `json:"date"`
+	Timestamp int64              `json:"timestamp"`
+	Rates     map[string]float64 `json:"rates"`
+}
+
+func loadRatesJSON(code, path string) (*Currency, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates file %s: %w", path, err)
+	}
+
+	var doc ratesDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates JSON from %s: %w", path, err)
+	}
+
+	rate, ok := doc.Rates[code]
+	if !ok {
+		return nil, fmt.Errorf("no exchange rate for %s in %s", code, path)
+	}
+	base := strings.ToUpper(doc.Base)
+	if base != "" && base != USD {
+		usdRate, ok := doc.Rates[USD]
+		if !ok || usdRate <= 0 {
+			return nil, fmt.Errorf("exchange rates in %s are based on %s and have no USD rate", path, base)
+		}
+		rate /= usdRate
+	}
+
+	date := doc.Date
+	if date == "" && doc.Timestamp > 0 {
+		date = time.Unix(doc.Timestamp, 0).UTC().Format(time.DateOnly)
+	}
+
+	return &Currency{Code: code, Rate: rate, Date: date}, nil
+}
+
+func loadRatesTable(code, path string) (*Currency, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(text, "#"); idx >= 0 {
+			text = strings.TrimSpace(text[:idx])
+		}
+		if text == "" {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) < 2 || strings.ToUpper(fields[0]) != code {
+			continue
+		}
+		rate, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate %q for %s at %s:%d", fields[1], code, path, line)
+		}
+		c := &Currency{Code: code, Rate: rate}
+		if len(fields) > 2 {
+			c.Date = fields[2]
+		} else if info, err := f.Stat(); err == nil {
+			c.Date = info.ModTime().UTC().Format(time.DateOnly)
+		}
+		return c, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates file %s: %w", path, err)
+	}
+	return nil, fmt.Errorf("no exchange rate for %s in %s", code, path)
+}
@@ -2,6 +2,7 @@ package cw
 
 import (
 	"context"
+	"math"
 	"sort"
 	"time"
 
@@ -20,11 +21,23 @@ func sortTimeSeriesDataPoints(points []types.TimeSeriesDataPoint) {
 const (
 	namespace             = "AWS/RDS"
 	dimensionName         = "DBInstanceIdentifier"
+	clusterDimensionName  = "DBClusterIdentifier"
 	cpuUtilizationId      = "cpu"
+	cpuSampleCountId      = "cpusamples"
+	cpuHourlyId           = "cpuhourly"
+	freeableMemoryDailyId = "freeablememdaily"
+	queriesId             = "queries"
+	commitThroughputId    = "commits"
+	dmlThroughputId       = "dml"
+	readIOPSId            = "readiops"
+	writeIOPSId           = "writeiops"
 	databaseConnectionsId = "connections"
 	freeableMemoryId      = "freeablemem"
 	writeThroughputId     = "write"
 	readThroughputId      = "read"
+	volumeBytesUsedId     = "volumebytes"
+	volumeReadIOPsId      = "volumereadiops"
+	volumeWriteIOPsId     = "volumewriteiops"
 )
 
 type CloudWatch struct {
@@ -37,12 +50,24 @@ func NewCloudWatch(awsConfig *aws.Config) *CloudWatch {
 	}
 }
 
-func (c *CloudWatch) GetMetrics(ctx context.Context, dbInstanceId *string, periodInDays int, statistic types.StatName) (*types.Metrics, error) {
+// GetMetrics returns the statistics of an instance over the last periodInDays days.
+// When since falls within that window, only the data points after it are used. A
+// since within the last hour still queries the last full hour, as the window ends
+// at the start of the current hour.
+func (c *CloudWatch) GetMetrics(ctx context.Context, dbInstanceId *string, periodInDays int, statistic types.StatName, since time.Time) (*types.Metrics, error) {
 
 	endTime := time.Now().UTC().Truncate(time.Hour)
 	startTime := endTime.AddDate(0, 0, (periodInDays)*-1)
+	if since.After(startTime) {
+		startTime = since.UTC()
+	}
+	if latest := endTime.Add(-time.Hour); startTime.After(latest) {
+		startTime = latest
+	}
 
 	period := int32(periodInDays * 24 * 60 * 60)
+	hourlyPeriod := int32(60 * 60)
+	dailyPeriod := int32(24 * 60 * 60)
 
 	input := &cloudwatch.GetMetricDataInput{
 		StartTime: aws.Time(startTime),
@@ -99,6 +124,57 @@ func (c *CloudWatch) GetMetrics(ctx context.Context, dbInstanceId *string, perio
 					Stat:   aws.String(statistic.String()),
 				},
 			},
+			{
+				Id: aws.String(cpuSampleCountId),
+				MetricStat: &cwTypes.MetricStat{
+					Metric: &cwTypes.Metric{
+						Namespace:  aws.String(namespace),
+						MetricName: aws.String(types.CPUUtilization.String()),
+						Dimensions: []cwTypes.Dimension{
+							{
+								Name:  aws.String(dimensionName),
+								Value: dbInstanceId,
+							},
+						},
+					},
+					Period: &period,
+					Stat:   aws.String(types.SampleCount.String()),
+				},
+			},
+			{
+				Id: aws.String(cpuHourlyId),
+				MetricStat: &cwTypes.MetricStat{
+					Metric: &cwTypes.Metric{
+						Namespace:  aws.String(namespace),
+						MetricName: aws.String(types.CPUUtilization.String()),
+						Dimensions: []cwTypes.Dimension{
+							{
+								Name:  aws.String(dimensionName),
+								Value: dbInstanceId,
+							},
+						},
+					},
+					Period: &hourlyPeriod,
+					Stat:   aws.String(types.Average.String()),
+				},
+			},
+			{
+				Id: aws.String(freeableMemoryDailyId),
+				MetricStat: &cwTypes.MetricStat{
+					Metric: &cwTypes.Metric{
+						Namespace:  aws.String(namespace),
+						MetricName: aws.String(types.FreeableMemory.String()),
+						Dimensions: []cwTypes.Dimension{
+							{
+								Name:  aws.String(dimensionName),
+								Value: dbInstanceId,
+							},
+						},
+					},
+					Period: &dailyPeriod,
+					Stat:   aws.String(types.Maximum.String()),
+				},
+			},
 			{
 				Id: aws.String(writeThroughputId),
 				MetricStat: &cwTypes.MetricStat{
@@ -136,6 +212,37 @@ func (c *CloudWatch) GetMetrics(ctx context.Context, dbInstanceId *string, perio
 		},
 	}
 
+	// Workload activity, used to tell instances kept alive only by monitoring.
+	// Metrics the engine does not publish (e.g. Queries on PostgreSQL) return no values.
+	for _, q := range []struct {
+		id     string
+		metric types.RdsMetricName
+	}{
+		{queriesId, types.Queries},
+		{commitThroughputId, types.CommitThroughput},
+		{dmlThroughputId, types.DMLThroughput},
+		{readIOPSId, types.ReadIOPS},
+		{writeIOPSId, types.WriteIOPS},
+	} {
+		input.MetricDataQueries = append(input.MetricDataQueries, cwTypes.MetricDataQuery{
+			Id: aws.String(q.id),
+			MetricStat: &cwTypes.MetricStat{
+				Metric: &cwTypes.Metric{
+					Namespace:  aws.String(namespace),
+					MetricName: aws.String(q.metric.String()),
+					Dimensions: []cwTypes.Dimension{
+						{
+							Name:  aws.String(dimensionName),
+							Value: dbInstanceId,
+						},
+					},
+				},
+				Period: &period,
+				Stat:   aws.String(statistic.String()),
+			},
+		})
+	}
+
 	output, err := c.cwClient.GetMetricData(ctx, input)
 
 	if err != nil {
@@ -144,8 +251,32 @@ func (c *CloudWatch) GetMetrics(ctx context.Context, dbInstanceId *string, perio
 
 	var m types.Metrics
 	metrics := make(map[types.RdsMetricName]types.Metric)
+	dataPoints := 0
+	var cpuVariation *float64
+	var freeableMemoryDaily []types.TimeSeriesDataPoint
 
 	for _, result := range output.MetricDataResults {
+		if *result.Id == cpuSampleCountId {
+			for _, value := range result.Values {
+				dataPoints = int(value)
+			}
+			continue
+		}
+		if *result.Id == cpuHourlyId {
+			cpuVariation = coefficientOfVariation(result.Values)
+			continue
+		}
+		if *result.Id == freeableMemoryDailyId {
+			for i, ts := range result.Timestamps {
+				freeableMemoryDaily = append(freeableMemoryDaily, types.TimeSeriesDataPoint{
+					Timestamp: ts,
+					Value:     result.Values[i],
+				})
+			}
+			sortTimeSeriesDataPoints(freeableMemoryDaily)
+			continue
+		}
+
 		var metricName types.RdsMetricName
 
 		switch *result.Id {
@@ -159,6 +290,16 @@ func (c *CloudWatch) GetMetrics(ctx context.Context, dbInstanceId *string, perio
 			metricName = types.WriteThroughput
 		case readThroughputId:
 			metricName = types.ReadThroughput
+		case queriesId:
+			metricName = types.Queries
+		case commitThroughputId:
+			metricName = types.CommitThroughput
+		case dmlThroughputId:
+			metricName = types.DMLThroughput
+		case readIOPSId:
+			metricName = types.ReadIOPS
+		case writeIOPSId:
+			metricName = types.WriteIOPS
 		}
 
 		for _, value := range result.Values {
@@ -171,11 +312,39 @@ func (c *CloudWatch) GetMetrics(ctx context.Context, dbInstanceId *string, perio
 	m = types.Metrics{
 		DBInstanceIdentifier: dbInstanceId,
 		InstanceMetrics:      metrics,
+		DataPoints:           dataPoints,
+		CPUVariation:         cpuVariation,
+		FreeableMemoryDaily:  freeableMemoryDaily,
+		Since:                startTime,
 	}
 
 	return &m, nil
 }
 
+// coefficientOfVariation returns the standard deviation of values divided by their
+// mean, or nil when there are fewer than two values or the mean is zero.
+func coefficientOfVariation(values []float64) *float64 {
+	if len(values) < 2 {
+		return nil
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if mean == 0 {
+		return nil
+	}
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	cv := math.Sqrt(squares/float64(len(values))) / mean
+	return &cv
+}
+
 func (c *CloudWatch) GetTimeSeriesMetrics(ctx context.Context, dbInstanceId *string, periodInDays int, statistic types.StatName) (*types.TimeSeriesMetrics, error) {
 	endTime := time.Now().UTC().Truncate(time.Hour)
 	startTime := endTime.AddDate(0, 0, periodInDays*-1)
@@ -342,3 +511,69 @@ func (c *CloudWatch) GetTimeSeriesMetrics(ctx context.Context, dbInstanceId *str
 		Metrics:              tsMetrics,
 	}, nil
 }
+
+// GetClusterStorageMetrics returns the average volume size and the total billed
+// read/write I/O operations of an Aurora cluster over the lookback period.
+func (c *CloudWatch) GetClusterStorageMetrics(ctx context.Context, dbClusterId *string, periodInDays int) (*types.ClusterStorageMetrics, error) {
+	endTime := time.Now().UTC().Truncate(time.Hour)
+	startTime := endTime.AddDate(0, 0, periodInDays*-1)
+
+	period := int32(periodInDays * 24 * 60 * 60)
+
+	query := func(id string, metricName types.RdsMetricName, stat types.StatName) cwTypes.MetricDataQuery {
+		return cwTypes.MetricDataQuery{
+			Id: aws.String(id),
+			MetricStat: &cwTypes.MetricStat{
+				Metric: &cwTypes.Metric{
+					Namespace:  aws.String(namespace),
+					MetricName: aws.String(metricName.String()),
+					Dimensions: []cwTypes.Dimension{
+						{
+							Name:  aws.String(clusterDimensionName),
+							Value: dbClusterId,
+						},
+					},
+				},
+				Period: &period,
+				Stat:   aws.String(stat.String()),
+			},
+		}
+	}
+
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(startTime),
+		EndTime:   aws.Time(endTime),
+		MetricDataQueries: []cwTypes.MetricDataQuery{
+			query(volumeBytesUsedId, types.VolumeBytesUsed, types.Average),
+			query(volumeReadIOPsId, types.VolumeReadIOPs, types.Sum),
+			query(volumeWriteIOPsId, types.VolumeWriteIOPs, types.Sum),
+		},
+	}
+
+	output, err := c.cwClient.GetMetricData(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	m := types.ClusterStorageMetrics{
+		DBClusterIdentifier: dbClusterId,
+	}
+
+	for _, result := range output.MetricDataResults {
+		if len(result.Values) == 0 {
+			continue
+		}
+		value := result.Values[0]
+
+		switch *result.Id {
+		case volumeBytesUsedId:
+			m.VolumeBytesUsed = &value
+		case volumeReadIOPsId:
+			m.VolumeReadIOPs = &value
+		case volumeWriteIOPsId:
+			m.VolumeWriteIOPs = &value
+		}
+	}
+
+	return &m, nil
+}
@@ -12,12 +12,22 @@ const (
 	FreeableMemory      RdsMetricName = "FreeableMemory"
 	WriteThroughput     RdsMetricName = "WriteThroughput"
 	ReadThroughput      RdsMetricName = "ReadThroughput"
+	VolumeBytesUsed     RdsMetricName = "VolumeBytesUsed"
+	VolumeReadIOPs      RdsMetricName = "VolumeReadIOPs"
+	VolumeWriteIOPs     RdsMetricName = "VolumeWriteIOPs"
+	Queries             RdsMetricName = "Queries"
+	CommitThroughput    RdsMetricName = "CommitThroughput"
+	DMLThroughput       RdsMetricName = "DMLThroughput"
+	ReadIOPS            RdsMetricName = "ReadIOPS"
+	WriteIOPS           RdsMetricName = "WriteIOPS"
 	Average             StatName      = "Average"
 	Maximum             StatName      = "Maximum"
 	P99                 StatName      = "p99"
 	P98                 StatName      = "p98"
 	P95                 StatName      = "p95"
 	P50                 StatName      = "p50"
+	Sum                 StatName      = "Sum"
+	SampleCount         StatName      = "SampleCount"
 )
 
 func (c RdsMetricName) String() string {
@@ -31,6 +41,17 @@ func (c StatName) String() string {
 type Metrics struct {
 	DBInstanceIdentifier *string
 	InstanceMetrics      map[RdsMetricName]Metric
+	// DataPoints is the number of CPUUtilization data points the statistics were
+	// computed from.
+	DataPoints int
+	// CPUVariation is the coefficient of variation (standard deviation / mean) of
+	// hourly average CPUUtilization, or nil when it could not be computed.
+	CPUVariation *float64
+	// FreeableMemoryDaily is the daily Maximum FreeableMemory, used to spot instance
+	// class changes the RDS events no longer cover.
+	FreeableMemoryDaily []TimeSeriesDataPoint
+	// Since is the start of the window the statistics cover.
+	Since time.Time
 }
 
 type Metric struct {
@@ -51,3 +72,13 @@ type TimeSeriesMetrics struct {
 	DBInstanceIdentifier *string
 	Metrics              map[RdsMetricName]TimeSeriesMetric
 }
+
+// ClusterStorageMetrics holds Aurora cluster volume usage over the lookback period.
+// VolumeBytesUsed is the average volume size; the I/O values are the total billed
+// read and write I/O operations across the period.
+type ClusterStorageMetrics struct {
+	DBClusterIdentifier *string
+	VolumeBytesUsed     *float64
+	VolumeReadIOPs      *float64
+	VolumeWriteIOPs     *float64
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/fogleman/gg"
 	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font"
@@ -12,6 +14,7 @@ import (
 	"golang.org/x/image/font/gofont/gomono"
 	"golang.org/x/image/font/gofont/goregular"
 
+	"github.com/luneo7/rds-right-size/internal/currency"
 	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
 )
 
@@ -107,8 +110,12 @@ func badgeColor(rec types.RecommendationType) color.Color {
 		return colorRed
 	case types.DownScale:
 		return colorGreen
-	case types.Terminate:
+	case types.Terminate, types.LikelyIdle:
 		return colorAmber
+	case types.StorageConfiguration:
+		return colorPurple
+	case types.Skipped, types.Unavailable:
+		return textLight
 	}
 	return textMedium
 }
@@ -137,6 +144,8 @@ func drawHeader(dc *gg.Context, rec *types.Recommendation, y float64) float64 {
 	instanceID := ""
 	if rec.DBInstanceIdentifier != nil {
 		instanceID = *rec.DBInstanceIdentifier
+	} else if rec.DBClusterIdentifier != nil {
+		instanceID = *rec.DBClusterIdentifier
 	}
 	setFont(dc, fontBold, fontSizeTitle, textDark)
 	dc.DrawString(instanceID, x+bw+12, y+fontSizeTitle)
@@ -157,6 +166,15 @@ func drawHeader(dc *gg.Context, rec *types.Recommendation, y float64) float64 {
 	if rec.DBClusterIdentifier != nil {
 		parts = append(parts, "cluster: "+*rec.DBClusterIdentifier)
 	}
+	if rec.DBInstanceStatus != nil && *rec.DBInstanceStatus != "available" {
+		parts = append(parts, "status: "+*rec.DBInstanceStatus)
+	}
+	if rec.RunningInstanceClass != nil {
+		parts = append(parts, "running: "+*rec.RunningInstanceClass)
+	}
+	if rec.RunningEngineVersion != nil {
+		parts = append(parts, "running engine: "+*rec.RunningEngineVersion)
+	}
 	if len(parts) > 0 {
 		setFont(dc, fontRegular, fontSizeSubtitle, textMedium)
 		subtitle := ""
@@ -187,6 +205,25 @@ func drawRecommendationInfo(dc *gg.Context, rec *types.Recommendation, region st
 	dc.DrawString(reason, x, y+fontSizeBody)
 	y += lineHeight
 
+	// Measured utilization
+	if rec.Metrics != nil {
+		setFont(dc, fontRegular, fontSizeSmall, textMedium)
+		for _, line := range metricsLines(rec.Metrics) {
+			dc.DrawString(line, x, y+fontSizeSmall)
+			y += lineHeight
+		}
+		setFont(dc, fontRegular, fontSizeBody, textMedium)
+	}
+
+	// Storage configuration cost model
+	if cost := rec.StorageCost; cost != nil {
+		dc.DrawString(fmt.Sprintf("Volume: %.1f GiB    I/O requests: %.1fM/mo", cost.VolumeGiB, cost.MonthlyIORequests/1e6), x, y+fontSizeBody)
+		y += lineHeight
+		dc.DrawString(fmt.Sprintf("Standard: %s/mo (I/O %s)    I/O-Optimized: %s/mo",
+			formatAmount(rec.Currency, cost.Standard.Total), formatAmount(rec.Currency, cost.Standard.IO), formatAmount(rec.Currency, cost.IOOptimized.Total)), x, y+fontSizeBody)
+		y += lineHeight
+	}
+
 	// Projected CPU + cost line
 	infoLine := ""
 	if rec.ProjectedCPU != nil {
@@ -196,9 +233,9 @@ func drawRecommendationInfo(dc *gg.Context, rec *types.Recommendation, region st
 		diff := *rec.MonthlyApproximatePriceDiff
 		costStr := ""
 		if diff > 0 {
-			costStr = fmt.Sprintf("Monthly cost increase: +$%.2f", diff)
+			costStr = "Monthly cost increase: +" + formatAmount(rec.Currency, diff)
 		} else if diff < 0 {
-			costStr = fmt.Sprintf("Monthly savings: $%.2f", diff*-1)
+			costStr = "Monthly savings: " + formatAmount(rec.Currency, diff*-1)
 		}
 		if infoLine != "" && costStr != "" {
 			infoLine += "    "
@@ -227,9 +264,16 @@ func drawRecommendationInfo(dc *gg.Context, rec *types.Recommendation, region st
 			costText := ""
 			if diff > 0 {
 				costColor = colorRed
-				costText = fmt.Sprintf("Monthly cost increase: +$%.2f/mo (+$%.2f/yr)", diff, diff*12)
+				costText = fmt.Sprintf("Monthly cost increase: +%s/mo (+%s/yr)", formatAmount(rec.Currency, diff), formatAmount(rec.Currency, diff*12))
 			} else if diff < 0 {
-				costText = fmt.Sprintf("Monthly savings: $%.2f/mo ($%.2f/yr)", diff*-1, diff*-12)
+				costText = fmt.Sprintf("Monthly savings: %s/mo (%s/yr)", formatAmount(rec.Currency, diff*-1), formatAmount(rec.Currency, diff*-12))
+			}
+			if costText != "" && rec.PricingModel != "" && rec.PricingModel != types.OnDemand {
+				if rec.OnDemandPriceFallback {
+					costText += " [" + string(rec.PricingModel) + ", on-demand fallback]"
+				} else {
+					costText += " [" + string(rec.PricingModel) + "]"
+				}
 			}
 			if costText != "" {
 				setFont(dc, fontBold, fontSizeBody, costColor)
@@ -264,10 +308,52 @@ func drawRecommendationInfo(dc *gg.Context, rec *types.Recommendation, region st
 	return y + sectionGap/2
 }
 
+// metricsLines formats the measured utilization as lines of text: the statistic,
+// CPU and memory, then throughput and connections.
+func metricsLines(metrics *types.RecommendationMetrics) []string {
+	measured := fmt.Sprintf("Measured %s over %d data points", metrics.Statistic, metrics.DataPoints)
+	if metrics.ClassChangedAt != nil {
+		measured += fmt.Sprintf(" since the class change on %s", metrics.ClassChangedAt.UTC().Format("2006-01-02"))
+	}
+	first := []string{measured}
+	if metrics.CPU != nil {
+		first = append(first, fmt.Sprintf("CPU: %.1f%%", *metrics.CPU))
+	}
+	if metrics.FreeableMemoryGiB != nil {
+		memory := fmt.Sprintf("Freeable memory: %.2f GiB", *metrics.FreeableMemoryGiB)
+		if metrics.FreeableMemoryPercent != nil {
+			memory += fmt.Sprintf(" (%.1f%%)", *metrics.FreeableMemoryPercent)
+		}
+		first = append(first, memory)
+	}
+
+	var second []string
+	if metrics.ReadThroughput != nil && metrics.WriteThroughput != nil {
+		throughput := fmt.Sprintf("Read: %.1f KB/s    Write: %.1f KB/s", *metrics.ReadThroughput/1024, *metrics.WriteThroughput/1024)
+		if metrics.BandwidthPercent != nil {
+			throughput += fmt.Sprintf(" (%.1f%% of max bandwidth)", *metrics.BandwidthPercent)
+		}
+		second = append(second, throughput)
+	}
+	if metrics.PeakConnections != nil {
+		connections := fmt.Sprintf("Peak connections: %.0f", *metrics.PeakConnections)
+		if metrics.ConnectionsPercent != nil && metrics.MaxConnections != nil {
+			connections += fmt.Sprintf(" (%.1f%% of %d)", *metrics.ConnectionsPercent, *metrics.MaxConnections)
+		}
+		second = append(second, connections)
+	}
+
+	lines := []string{strings.Join(first, "    ")}
+	if len(second) > 0 {
+		lines = append(lines, strings.Join(second, "    "))
+	}
+	return lines
+}
+
 // drawComparison draws the current vs target instance comparison cards side by side.
 // Returns the Y position after the cards.
 func drawComparison(dc *gg.Context, rec *types.Recommendation, region string, y float64) float64 {
-	if rec.Recommendation == types.Terminate || rec.CurrentInstanceProperties == nil || rec.TargetInstanceProperties == nil {
+	if rec.Recommendation.RemovesInstance() || rec.CurrentInstanceProperties == nil || rec.TargetInstanceProperties == nil {
 		return y
 	}
 
@@ -287,8 +373,8 @@ func drawComparison(dc *gg.Context, rec *types.Recommendation, region string, y
 		targetName = *rec.RecommendedInstanceType
 	}
 
-	currentPrice := current.GetPrice(region)
-	targetPrice := target.GetPrice(region)
+	currentPrice := rec.Currency.Convert(current.GetInstancePrice(region, rec.PricingModel, aws.ToString(rec.StorageType)))
+	targetPrice := rec.Currency.Convert(target.GetInstancePrice(region, rec.PricingModel, aws.ToString(rec.StorageType)))
 
 	// Build card content
 	type cardRow struct {
@@ -318,12 +404,12 @@ func drawComparison(dc *gg.Context, rec *types.Recommendation, region string, y
 		targetRows = append(targetRows, cardRow{"Max Conns", fmt.Sprintf("%d", *target.MaxConnections)})
 	}
 	currentRows = append(currentRows,
-		cardRow{"Price/hr", fmt.Sprintf("$%.4f", currentPrice)},
-		cardRow{"Price/mo", fmt.Sprintf("$%.2f", currentPrice*730)},
+		cardRow{"Price/hr", formatRate(rec.Currency, currentPrice)},
+		cardRow{"Price/mo", formatAmount(rec.Currency, currentPrice*730)},
 	)
 	targetRows = append(targetRows,
-		cardRow{"Price/hr", fmt.Sprintf("$%.4f", targetPrice)},
-		cardRow{"Price/mo", fmt.Sprintf("$%.2f", targetPrice*730)},
+		cardRow{"Price/hr", formatRate(rec.Currency, targetPrice)},
+		cardRow{"Price/mo", formatAmount(rec.Currency, targetPrice*730)},
 	)
 
 	maxRows := len(currentRows)
@@ -427,9 +513,15 @@ func estimateInstanceHeight(rec *types.Recommendation) float64 {
 	if rec.ClusterEqualized {
 		h += lineHeight
 	}
+	if rec.StorageCost != nil {
+		h += lineHeight * 2
+	}
+	if rec.Metrics != nil {
+		h += lineHeight * float64(len(metricsLines(rec.Metrics)))
+	}
 	h += sectionGap / 2
 	// Comparison cards
-	if rec.Recommendation != types.Terminate && rec.CurrentInstanceProperties != nil && rec.TargetInstanceProperties != nil {
+	if !rec.Recommendation.RemovesInstance() && rec.CurrentInstanceProperties != nil && rec.TargetInstanceProperties != nil {
 		rows := 6 // vCPU, mem, price/hr, price/mo + possible BW + conns
 		if rec.CurrentInstanceProperties.MaxBandwidth != nil {
 			rows++
@@ -462,3 +554,24 @@ func estimateInstanceHeight(rec *types.Recommendation) float64 {
 	h += sectionGap // bottom margin
 	return h
 }
+
+// formatAmount formats an amount for drawing, spelling out the currency code when
+// the embedded fonts have no glyph for the currency symbol.
+func formatAmount(c *currency.Currency, amount float64) string {
+	return drawableCurrency(c, c.Format(amount))
+}
+
+// formatRate is formatAmount for hourly rates.
+func formatRate(c *currency.Currency, amount float64) string {
+	return drawableCurrency(c, c.FormatRate(amount))
+}
+
+func drawableCurrency(c *currency.Currency, formatted string) string {
+	symbol := c.Symbol()
+	for _, r := range symbol {
+		if fontRegular.Index(r) == 0 || fontBold.Index(r) == 0 {
+			return strings.Replace(formatted, symbol, c.Code+" ", 1)
+		}
+	}
+	return formatted
+}
@@ -34,6 +34,8 @@ func ExportInstancePNG(rec *types.Recommendation, region string, outputDir strin
 	instanceID := "instance"
 	if rec.DBInstanceIdentifier != nil {
 		instanceID = sanitizeFilename(*rec.DBInstanceIdentifier)
+	} else if rec.DBClusterIdentifier != nil {
+		instanceID = sanitizeFilename(*rec.DBClusterIdentifier) + "-storage"
 	}
 	filename := filepath.Join(outputDir, instanceID+".png")
 
@@ -112,7 +114,7 @@ func renderAndDrawCharts(dc *gg.Context, rec *types.Recommendation, y float64) f
 		var projectedValues []float64
 
 		// Generate projected CPU values when scaling with different vCPU counts
-		canProject := rec.Recommendation != types.Terminate &&
+		canProject := !rec.Recommendation.RemovesInstance() &&
 			rec.CurrentInstanceProperties != nil &&
 			rec.TargetInstanceProperties != nil &&
 			rec.CurrentInstanceProperties.Vcpu != rec.TargetInstanceProperties.Vcpu
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// decodeBulkPricing streams a bulk pricing JSON document token by token and keeps
+// only the products accepted by keep, with their on-demand and reserved terms.
+// Every other product and term is skipped as it is read. The kept products and
+// their terms stay in memory, so the terms are compacted to what onDemandPrice and
+// reservedPrices read (USD prices, standard reserved offerings); memory grows with
+// the kept entries rather than with the size of the file (several hundred
+// megabytes per region).
+//
+// AWS writes "products" before "terms". Should a file list terms first, they are
+// skipped and read in a second pass once the products are known.
+func decodeBulkPricing(r io.ReadSeeker, keep func(product bulkProduct) bool) (*bulkPricingResponse, error) {
+	bulk := &bulkPricingResponse{Products: make(map[string]bulkProduct)}
+	bulk.Terms.OnDemand = make(map[string]map[string]bulkTerm)
+	bulk.Terms.Reserved = make(map[string]map[string]bulkTerm)
+
+	dec := json.NewDecoder(r)
+	productsSeen, termsSkipped := false, false
+	err := decodeObject(dec, func(key string) error {
+		switch key {
+		case "publicationDate":
+			return dec.Decode(&bulk.PublicationDate)
+		case "products":
+			productsSeen = true
+			return decodeObject(dec, func(sku string) error {
+				var product bulkProduct
+				if err := dec.Decode(&product); err != nil {
+					return fmt.Errorf("product %s: %w", sku, err)
+				}
+				if keep(product) {
+					bulk.Products[sku] = product
+				}
+				return nil
+			})
+		case "terms":
+			if !productsSeen {
+				termsSkipped = true
+				return skipValue(dec)
+			}
+			return decodeTerms(dec, bulk)
+		default:
+			return skipValue(dec)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if termsSkipped {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to rewind for terms: %w", err)
+		}
+		dec = json.NewDecoder(r)
+		err = decodeObject(dec, func(key string) error {
+			if key == "terms" {
+				return decodeTerms(dec, bulk)
+			}
+			return skipValue(dec)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bulk, nil
+}
+
+// decodeTerms reads the "terms" object, keeping the on-demand and reserved terms of
+// the products already in bulk.
+func decodeTerms(dec *json.Decoder, bulk *bulkPricingResponse) error {
+	return decodeObject(dec, func(termType string) error {
+		var terms map[string]map[string]bulkTerm
+		switch termType {
+		case "OnDemand":
+			terms = bulk.Terms.OnDemand
+		case "Reserved":
+			terms = bulk.Terms.Reserved
+		default:
+			return skipValue(dec)
+		}
+		return decodeObject(dec, func(sku string) error {
+			if _, ok := bulk.Products[sku]; !ok {
+				return skipValue(dec)
+			}
+			var skuTerms map[string]bulkTerm
+			if err := dec.Decode(&skuTerms); err != nil {
+				return fmt.Errorf("%s terms of %s: %w", termType, sku, err)
+			}
+			compactTerms(skuTerms, termType == "Reserved")
+			terms[sku] = skuTerms
+			return nil
+		})
+	})
+}
+
+// compactTerms drops what onDemandPrice and reservedPrices never read from a SKU's
+// terms: prices in other currencies, dimensions without a USD price and, for
+// reserved terms, offerings reservedPricingModel does not map to a pricing model.
+func compactTerms(terms map[string]bulkTerm, reserved bool) {
+	for code, term := range terms {
+		if reserved {
+			if _, _, ok := reservedPricingModel(term); !ok {
+				delete(terms, code)
+				continue
+			}
+		}
+		for id, dim := range term.PriceDimensions {
+			usd, ok := dim.PricePerUnit["USD"]
+			if !ok {
+				delete(term.PriceDimensions, id)
+				continue
+			}
+			if len(dim.PricePerUnit) > 1 {
+				dim.PricePerUnit = map[string]string{"USD": usd}
+				term.PriceDimensions[id] = dim
+			}
+		}
+		if len(term.PriceDimensions) == 0 {
+			delete(terms, code)
+		}
+	}
+}
+
+// decodeObject reads a JSON object, calling fn for each key with the decoder
+// positioned at its value. fn must consume the value.
+func decodeObject(dec *json.Decoder, fn func(key string) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return err
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// objectKey reads the next object key.
+func objectKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("expected object key, got %v", tok)
+	}
+	return key, nil
+}
+
+// expectDelim reads the next token and checks that it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %q, got %v", delim, tok)
+	}
+	return nil
+}
+
+// skipValue consumes the next value without decoding it, token by token, so even
+// large skipped objects are never held in memory.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+)
+
+const (
+	// syntheticProducts is the number of products in the synthetic bulk file; one in
+	// syntheticAuroraEvery is an Aurora MySQL instance, roughly as in a real region
+	syntheticProducts    = 20000
+	syntheticAuroraEvery = 20
+
+	// heapSampleBytes is how often, in bytes read, the live heap is sampled
+	heapSampleBytes = 1 << 20
+)
+
+var (
+	syntheticOnce        sync.Once
+	syntheticProductsDoc []byte
+	syntheticTermsDoc    []byte
+)
+
+// syntheticBulkPricing builds a bulk pricing document with on-demand and reserved
+// terms for every product, listing products first as AWS does or terms first.
+func syntheticBulkPricing(termsFirst bool) []byte {
+	var products, onDemand, reserved strings.Builder
+	for i := 0; i < syntheticProducts; i++ {
+		sep := ","
+		if i == 0 {
+			sep = ""
+		}
+		engine := "PostgreSQL"
+		if i%syntheticAuroraEvery == 0 {
+			engine = "Aurora MySQL"
+		}
+		sku := fmt.Sprintf("SKU%08d", i)
+		fmt.Fprintf(&products, `%s"%s":{"sku":"%s","productFamily":"Database Instance","attributes":{"instanceType":"db.r6g.large","databaseEngine":"%s","deploymentOption":"Single-AZ","storage":"EBS Only","usagetype":"InstanceUsage:db.r6g.large","vcpu":"2","memory":"16 GiB","networkPerformance":"Up to 10 Gigabit","currentGeneration":"Yes","location":"US East (N. Virginia)","licenseModel":"No license required"}}`,
+			sep, sku, sku, engine)
+		fmt.Fprintf(&onDemand, `%s"%s":{"%s.JRTCKXETXF":{"offerTermCode":"JRTCKXETXF","sku":"%s","effectiveDate":"2024-01-01T00:00:00Z","priceDimensions":{"%s.JRTCKXETXF.6YS6EN2CT7":{"unit":"Hrs","description":"On-demand hourly rate","pricePerUnit":{"USD":"0.2600000000"}}},"termAttributes":{}}}`,
+			sep, sku, sku, sku, sku)
+		reserved.WriteString(sep + `"` + sku + `":{`)
+		for j, option := range []string{"No Upfront", "Partial Upfront", "All Upfront"} {
+			if j > 0 {
+				reserved.WriteString(",")
+			}
+			fmt.Fprintf(&reserved, `"%s.T%d":{"offerTermCode":"T%d","sku":"%s","effectiveDate":"2024-01-01T00:00:00Z","priceDimensions":{"%s.T%d.H":{"unit":"Hrs","description":"Reserved hourly rate","pricePerUnit":{"USD":"0.1700000000"}},"%s.T%d.Q":{"unit":"Quantity","description":"Upfront fee","pricePerUnit":{"USD":"1000"}}},"termAttributes":{"LeaseContractLength":"1yr","OfferingClass":"standard","PurchaseOption":"%s"}}`,
+				sku, j, j, sku, sku, j, sku, j, option)
+		}
+		reserved.WriteString("}")
+	}
+
+	productsJSON := `"products":{` + products.String() + `}`
+	termsJSON := `"terms":{"OnDemand":{` + onDemand.String() + `},"Reserved":{` + reserved.String() + `}}`
+	if termsFirst {
+		return []byte(`{"formatVersion":"v1.0","publicationDate":"2024-01-01T00:00:00Z",` + termsJSON + `,` + productsJSON + `}`)
+	}
+	return []byte(`{"formatVersion":"v1.0","publicationDate":"2024-01-01T00:00:00Z",` + productsJSON + `,` + termsJSON + `}`)
+}
+
+// pricingDoc is a small bulk pricing document with an Aurora MySQL and a PostgreSQL
+// instance. The Aurora SKU also has a CNY price, a convertible offering and a 3yr
+// All Upfront offering priced only by its upfront fee.
+const (
+	pricingDocProducts = `"products":{` +
+		`"AURORA":{"sku":"AURORA","productFamily":"Database Instance","attributes":{"instanceType":"db.r6g.large","databaseEngine":"Aurora MySQL","deploymentOption":"Single-AZ","storage":"EBS Only","usagetype":"InstanceUsage:db.r6g.large","vcpu":"2","memory":"16 GiB","networkPerformance":"Up to 10 Gigabit","currentGeneration":"Yes"}},` +
+		`"PG":{"sku":"PG","productFamily":"Database Instance","attributes":{"instanceType":"db.r6g.large","databaseEngine":"PostgreSQL","deploymentOption":"Single-AZ","storage":"EBS Only","usagetype":"InstanceUsage:db.r6g.large","vcpu":"2","memory":"16 GiB","currentGeneration":"Yes"}}}`
+	pricingDocTerms = `"terms":{"OnDemand":{` +
+		`"AURORA":{"AURORA.OD":{"priceDimensions":{"AURORA.OD.H":{"unit":"Hrs","pricePerUnit":{"USD":"0.2600000000","CNY":"1.9"}},"AURORA.OD.X":{"unit":"Hrs","pricePerUnit":{"CNY":"1.9"}}},"termAttributes":{}}},` +
+		`"PG":{"PG.OD":{"priceDimensions":{"PG.OD.H":{"unit":"Hrs","pricePerUnit":{"USD":"0.2500000000"}}},"termAttributes":{}}}},` +
+		`"Reserved":{"AURORA":{` +
+		`"AURORA.NU":{"priceDimensions":{"AURORA.NU.H":{"unit":"Hrs","pricePerUnit":{"USD":"0.1700000000"}}},"termAttributes":{"LeaseContractLength":"1yr","OfferingClass":"standard","PurchaseOption":"No Upfront"}},` +
+		`"AURORA.PU":{"priceDimensions":{"AURORA.PU.H":{"unit":"Hrs","pricePerUnit":{"USD":"0.0800000000"}},"AURORA.PU.Q":{"unit":"Quantity","pricePerUnit":{"USD":"876"}}},"termAttributes":{"LeaseContractLength":"1yr","OfferingClass":"standard","PurchaseOption":"Partial Upfront"}},` +
+		`"AURORA.AU":{"priceDimensions":{"AURORA.AU.H":{"unit":"Hrs","pricePerUnit":{"USD":"0"}},"AURORA.AU.Q":{"unit":"Quantity","pricePerUnit":{"USD":"2628"}}},"termAttributes":{"LeaseContractLength":"3yr","OfferingClass":"standard","PurchaseOption":"All Upfront"}},` +
+		`"AURORA.CV":{"priceDimensions":{"AURORA.CV.H":{"unit":"Hrs","pricePerUnit":{"USD":"0.1000000000"}}},"termAttributes":{"LeaseContractLength":"1yr","OfferingClass":"convertible","PurchaseOption":"No Upfront"}}}}}`
+)
+
+// TestDecodeBulkPricing checks the prices read from a streamed document, with
+// products listed first as AWS does and with terms first.
+func TestDecodeBulkPricing(t *testing.T) {
+	wantReserved := map[types.PricingModel]float64{
+		types.RI1yNoUpfront:      0.17,
+		types.RI1yPartialUpfront: 0.08 + 876.0/hoursPerYear,
+		types.RI3yAllUpfront:     2628.0 / (3 * hoursPerYear),
+	}
+
+	for name, doc := range map[string]string{
+		"products-first": `{"formatVersion":"v1.0","publicationDate":"2024-01-01T00:00:00Z",` + pricingDocProducts + `,` + pricingDocTerms + `}`,
+		"terms-first":    `{"formatVersion":"v1.0",` + pricingDocTerms + `,` + pricingDocProducts + `,"publicationDate":"2024-01-01T00:00:00Z"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := bulkInstanceData(strings.NewReader(doc), "aurora-mysql", "us-east-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.PublicationDate != "2024-01-01T00:00:00Z" {
+				t.Errorf("PublicationDate = %q, want 2024-01-01T00:00:00Z", data.PublicationDate)
+			}
+			if len(data.Instances) != 1 {
+				t.Fatalf("got %d instances, want 1: %v", len(data.Instances), data.Instances)
+			}
+			info, ok := data.Instances["db.r6g.large"]
+			if !ok {
+				t.Fatalf("db.r6g.large missing: %v", data.Instances)
+			}
+			if info.Price != 0.26 {
+				t.Errorf("Price = %v, want 0.26", info.Price)
+			}
+			if info.VCPUs != 2 || info.MemoryGiB != 16 {
+				t.Errorf("VCPUs, MemoryGiB = %d, %d, want 2, 16", info.VCPUs, info.MemoryGiB)
+			}
+			if len(info.ReservedPrices) != len(wantReserved) {
+				t.Errorf("ReservedPrices = %v, want %v", info.ReservedPrices, wantReserved)
+			}
+			for model, want := range wantReserved {
+				if got := info.ReservedPrices[model]; math.Abs(got-want) > 1e-9 {
+					t.Errorf("ReservedPrices[%s] = %v, want %v", model, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestCompactTerms checks that decoded terms keep only what the price functions read.
+func TestCompactTerms(t *testing.T) {
+	bulk, err := decodeBulkPricing(strings.NewReader(`{`+pricingDocProducts+`,`+pricingDocTerms+`}`), func(product bulkProduct) bool {
+		return product.Attributes.DatabaseEngine == "Aurora MySQL"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bulk.Terms.OnDemand["PG"]; ok {
+		t.Error("terms of a skipped product were kept")
+	}
+	onDemand := bulk.Terms.OnDemand["AURORA"]["AURORA.OD"]
+	if len(onDemand.PriceDimensions) != 1 {
+		t.Errorf("on-demand dimensions = %v, want only the USD one", onDemand.PriceDimensions)
+	}
+	if prices := onDemand.PriceDimensions["AURORA.OD.H"].PricePerUnit; len(prices) != 1 || prices["USD"] != "0.2600000000" {
+		t.Errorf("on-demand prices = %v, want only USD 0.26", prices)
+	}
+	if _, ok := bulk.Terms.Reserved["AURORA"]["AURORA.CV"]; ok {
+		t.Error("convertible reserved offering was kept")
+	}
+	if n := len(bulk.Terms.Reserved["AURORA"]); n != 3 {
+		t.Errorf("kept %d reserved offerings, want 3", n)
+	}
+}
+
+// heapSampler is a reader that records the peak live heap while the document is
+// read. Each sample collects garbage first, so only reachable memory is counted.
+type heapSampler struct {
+	r      io.ReadSeeker
+	unread int
+	peak   uint64
+}
+
+func (s *heapSampler) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.unread += n
+	if s.unread >= heapSampleBytes {
+		s.unread = 0
+		s.sample()
+	}
+	return n, err
+}
+
+func (s *heapSampler) Seek(offset int64, whence int) (int64, error) {
+	return s.r.Seek(offset, whence)
+}
+
+func (s *heapSampler) sample() {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if stats.HeapAlloc > s.peak {
+		s.peak = stats.HeapAlloc
+	}
+}
+
+// BenchmarkDecodeBulkPricing compares the peak live heap of decoding a synthetic
+// bulk pricing file whole against streaming it, reported as peak-heap-B/op. The
+// document itself is held in memory before and after, so it is not counted.
+func BenchmarkDecodeBulkPricing(b *testing.B) {
+	syntheticOnce.Do(func() {
+		syntheticProductsDoc = syntheticBulkPricing(false)
+		syntheticTermsDoc = syntheticBulkPricing(true)
+	})
+	keep := func(product bulkProduct) bool {
+		return product.Attributes.DatabaseEngine == "Aurora MySQL"
+	}
+
+	for _, bench := range []struct {
+		name   string
+		doc    []byte
+		decode func(r io.ReadSeeker) (*bulkPricingResponse, error)
+	}{
+		{"full", syntheticProductsDoc, func(r io.ReadSeeker) (*bulkPricingResponse, error) {
+			var bulk bulkPricingResponse
+			err := json.NewDecoder(r).Decode(&bulk)
+			return &bulk, err
+		}},
+		{"streaming", syntheticProductsDoc, func(r io.ReadSeeker) (*bulkPricingResponse, error) {
+			return decodeBulkPricing(r, keep)
+		}},
+		{"streaming-terms-first", syntheticTermsDoc, func(r io.ReadSeeker) (*bulkPricingResponse, error) {
+			return decodeBulkPricing(r, keep)
+		}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(bench.doc)))
+
+			var peak uint64
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+				var base runtime.MemStats
+				runtime.ReadMemStats(&base)
+
+				sampler := &heapSampler{r: bytes.NewReader(bench.doc)}
+				bulk, err := bench.decode(sampler)
+				if err != nil {
+					b.Fatal(err)
+				}
+				sampler.sample()
+				if len(bulk.Products) == 0 || len(bulk.Terms.OnDemand) == 0 || len(bulk.Terms.Reserved) == 0 {
+					b.Fatal("decoded no products or terms")
+				}
+				if sampler.peak > base.HeapAlloc {
+					peak += sampler.peak - base.HeapAlloc
+				}
+			}
+			b.ReportMetric(float64(peak)/float64(b.N), "peak-heap-B/op")
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+)
+
+// endOfLifeFamilies lists the instance families that AWS has retired or announced
+// end of support for on Aurora. Listed classes are marked as previous generation
+// even when the pricing files still call them current. The built-in list carries no
+// end-of-support dates; they are supplied only through GenerateOptions.EOLFile,
+// which can also add families or single classes without a release.
+var endOfLifeFamilies = []string{
+	"db.t1",
+	"db.m1",
+	"db.m2",
+	"db.m3",
+	"db.r3",
+	"db.m4",
+	"db.r4",
+	"db.t2",
+}
+
+// loadEndOfLife returns the end-of-life table of families and classes to their
+// end-of-support date ("" when not supplied): the built-in families without dates,
+// with the entries of path (a JSON object, e.g. {"db.r4": "2025-06-30"}) added over
+// them.
+func loadEndOfLife(path string) (map[string]string, error) {
+	table := make(map[string]string, len(endOfLifeFamilies))
+	for _, family := range endOfLifeFamilies {
+		table[family] = ""
+	}
+	if path == "" {
+		return table, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read end-of-life file %s: %w", path, err)
+	}
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse end-of-life JSON from %s: %w", path, err)
+	}
+	maps.Copy(table, entries)
+	return table, nil
+}
+
+// endOfLife looks up a class in the end-of-life table, first by class and then by
+// family, returning its end-of-support date ("" when unknown) and whether it is listed.
+func endOfLife(table map[string]string, cls string) (string, bool) {
+	if date, ok := table[cls]; ok {
+		return date, true
+	}
+	date, ok := table[instanceFamilyKey(cls)]
+	return date, ok
+}
@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/luneo7/rds-right-size/internal/cache"
 	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
 	"github.com/luneo7/rds-right-size/internal/util"
 )
@@ -22,20 +26,45 @@ type GenerateOptions struct {
 	TargetRegions string              // Comma-separated target regions, or "all" for all enabled regions
 	Output        string              // Output file path
 	OnStatus      func(status string) // Optional status callback for progress
+	Cache         *cache.Cache        // Optional download cache for pricing files
+
+	// PricingDir holds previously downloaded bulk pricing files, one
+	// "<region>/index.json" per region (see DownloadInputs). When set, pricing is
+	// read from it instead of the network, and "all" target regions means every
+	// region in the directory.
+	PricingDir string
+	// OrderableFile is a saved DescribeOrderableDBInstanceOptions dump (the AWS CLI's
+	// JSON output; comma-separate several files). When set, it replaces the live
+	// API call. Defaults to PricingDir's orderable_options.json when that exists;
+	// with PricingDir and neither, no engine version data is generated.
+	OrderableFile string
+	// EOLFile is a JSON object of instance families or classes to end-of-support
+	// dates (YYYY-MM-DD, or "" when unannounced), added to the built-in table.
+	EOLFile string
+	// LegacyFormat writes the bare instance types map (schema version 1) with
+	// storage rates in a sidecar file, for binaries that predate the versioned file.
+	LegacyFormat bool
 }
 
+// bulkPricingSource is recorded as the pricing source of generated files when
+// the bulk pricing files are downloaded.
+const bulkPricingSource = "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonRDS/current"
+
 // orderableClassInfo holds per-instance-class data collected from DescribeOrderableDBInstanceOptions.
 type orderableClassInfo struct {
-	engineVersions []string // all supported engine versions (for min version calc)
+	engineVersions []string            // all supported engine versions (for min version calc)
+	regionVersions map[string][]string // region -> supported engine major/minor versions
 }
 
 // GenerateInstanceTypes builds an instance types JSON file by:
-// 1. Discovering target regions from the public AWS pricing region index
-// 2. Downloading bulk pricing JSON per region for hardware specs, pricing, and availability
-// 3. Collecting engine version support from RDS DescribeOrderableDBInstanceOptions (home region)
-// 4. Computing max connections per engine
-// 5. Building up/down linked lists within each instance family
-// 6. Writing the result to a JSON file
+//  1. Discovering target regions from the public AWS pricing region index
+//  2. Downloading bulk pricing JSON per region for hardware specs, pricing, and availability
+//  3. Collecting engine version support from RDS DescribeOrderableDBInstanceOptions in each target region
+//  4. Computing max connections per engine
+//  5. Building up/down linked lists within each instance family
+//  6. Writing the result to a versioned JSON file (types.InstanceTypesFile) with the
+//     Aurora storage and I/O rates and the generation metadata; with
+//     opts.LegacyFormat, the bare map plus a sidecar file (see types.StoragePricingPath)
 //
 // When engine is "both", it runs the pipeline for aurora-mysql and aurora-postgresql
 // separately, then merges the results using engine-prefixed keys (e.g., "aurora-mysql:db.r6g.large").
@@ -52,25 +81,52 @@ func GenerateInstanceTypes(ctx context.Context, cfg aws.Config, opts GenerateOpt
 		engine = "both"
 	}
 
+	if opts.OrderableFile == "" && opts.PricingDir != "" {
+		if path := filepath.Join(opts.PricingDir, OrderableFileName); fileExists(path) {
+			opts.OrderableFile = path
+		} else {
+			// Generation from a pricing directory stays offline
+			status(fmt.Sprintf("Warning: no %s in %s and no orderable file given; generating without engine version data", OrderableFileName, opts.PricingDir))
+		}
+	}
+
+	eol, err := loadEndOfLife(opts.EOLFile)
+	if err != nil {
+		return err
+	}
+
 	// Discover target regions
-	targetRegions, err := resolveTargetRegions(ctx, opts.TargetRegions, opts.Region, status)
+	targetRegions, err := resolveTargetRegions(ctx, opts, status)
 	if err != nil {
 		return fmt.Errorf("failed to resolve target regions: %w", err)
 	}
 	status(fmt.Sprintf("Target regions (%d): %s", len(targetRegions), strings.Join(targetRegions, ", ")))
 
 	var instanceTypes types.InstanceTypes
+	storagePricing := make(types.StoragePricing)
+	published := make(map[string]string)
+	engines := []string{engine}
 
 	if engine == "both" {
-		engines := []string{"aurora-mysql", "aurora-postgresql"}
+		engines = []string{"aurora-mysql", "aurora-postgresql"}
 		instanceTypes = make(types.InstanceTypes)
 
 		for _, eng := range engines {
 			status(fmt.Sprintf("--- Generating for %s ---", eng))
-			engineTypes, err := generateForEngine(ctx, cfg, eng, opts.Region, targetRegions, status)
+			engineTypes, engineStorage, enginePublished, err := generateForEngine(ctx, cfg, opts, eng, targetRegions, eol, status)
 			if err != nil {
 				return fmt.Errorf("failed generating for %s: %w", eng, err)
 			}
+			for region, date := range enginePublished {
+				published[region] = date
+			}
+
+			// Aurora storage rates are the same for both engines; keep the first seen
+			for region, prices := range engineStorage {
+				if _, ok := storagePricing[region]; !ok {
+					storagePricing[region] = prices
+				}
+			}
 
 			// Merge with engine-prefixed keys
 			for cls, props := range engineTypes {
@@ -90,12 +146,37 @@ func GenerateInstanceTypes(ctx context.Context, cfg aws.Config, opts GenerateOpt
 			status(fmt.Sprintf("Merged %d instance types for %s", len(engineTypes), eng))
 		}
 	} else {
-		instanceTypes, err = generateForEngine(ctx, cfg, engine, opts.Region, targetRegions, status)
+		instanceTypes, storagePricing, published, err = generateForEngine(ctx, cfg, opts, engine, targetRegions, eol, status)
 		if err != nil {
 			return err
 		}
 	}
 
+	if opts.LegacyFormat {
+		return writeLegacyInstanceTypes(instanceTypes, storagePricing, opts.Output, status)
+	}
+
+	pricingSource := bulkPricingSource
+	if opts.PricingDir != "" {
+		pricingSource = "pricing-dir:" + opts.PricingDir
+	}
+	file := types.InstanceTypesFile{
+		SchemaVersion: types.InstanceTypesSchemaVersion,
+		Metadata: types.InstanceTypesMetadata{
+			GeneratedAt:      time.Now().UTC().Truncate(time.Second),
+			Generator:        "rds-right-size generate-types",
+			Engines:          engines,
+			HomeRegion:       opts.Region,
+			TargetRegions:    targetRegions,
+			PricingSource:    pricingSource,
+			PricingPublished: published,
+		},
+		InstanceTypes: instanceTypes,
+	}
+	if len(storagePricing) > 0 {
+		file.StoragePricing = storagePricing
+	}
+
 	// Write JSON
 	status("Writing JSON file...")
 	output := opts.Output
@@ -103,6 +184,27 @@ func GenerateInstanceTypes(ctx context.Context, cfg aws.Config, opts GenerateOpt
 		output = "aurora_instance_types.json"
 	}
 
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", output, err)
+	}
+
+	status(fmt.Sprintf("Done! Written %d instance types to %s", len(instanceTypes), output))
+	return nil
+}
+
+// writeLegacyInstanceTypes writes the bare instance types map, with storage and
+// I/O rates in a sidecar file so the map keeps its format.
+func writeLegacyInstanceTypes(instanceTypes types.InstanceTypes, storagePricing types.StoragePricing, output string, status func(string)) error {
+	status("Writing JSON file (legacy format)...")
+	if output == "" {
+		output = "aurora_instance_types.json"
+	}
+
 	data, err := json.MarshalIndent(instanceTypes, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
@@ -112,15 +214,29 @@ func GenerateInstanceTypes(ctx context.Context, cfg aws.Config, opts GenerateOpt
 		return fmt.Errorf("failed to write file %s: %w", output, err)
 	}
 
+	if len(storagePricing) > 0 {
+		storageOutput := types.StoragePricingPath(output)
+		storageData, err := json.MarshalIndent(storagePricing, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal storage pricing JSON: %w", err)
+		}
+		if err := os.WriteFile(storageOutput, storageData, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", storageOutput, err)
+		}
+		status(fmt.Sprintf("Written storage pricing for %d regions to %s", len(storagePricing), storageOutput))
+	}
+
 	status(fmt.Sprintf("Done! Written %d instance types to %s", len(instanceTypes), output))
 	return nil
 }
 
 // resolveTargetRegions determines which AWS regions to include in the generated JSON.
-// If targetRegions is "all" or empty, it fetches the public AWS pricing region index.
-// Otherwise, it parses the comma-separated list.
+// If targetRegions is "all" or empty, it fetches the public AWS pricing region index,
+// or lists the regions of opts.PricingDir. Otherwise, it parses the comma-separated list.
 // warn is called (non-nil) when a non-fatal fallback occurs.
-func resolveTargetRegions(ctx context.Context, targetRegions string, homeRegion string, warn func(string)) ([]string, error) {
+func resolveTargetRegions(ctx context.Context, opts GenerateOptions, warn func(string)) ([]string, error) {
+	targetRegions := opts.TargetRegions
+	homeRegion := opts.Region
 	if targetRegions != "" && targetRegions != "all" {
 		// Parse comma-separated list
 		parts := strings.Split(targetRegions, ",")
@@ -134,8 +250,19 @@ func resolveTargetRegions(ctx context.Context, targetRegions string, homeRegion
 		return regions, nil
 	}
 
+	if opts.PricingDir != "" {
+		regions, err := localPricingRegions(opts.PricingDir)
+		if err != nil {
+			return nil, err
+		}
+		if len(regions) == 0 {
+			return nil, fmt.Errorf("no bulk pricing files found in %s", opts.PricingDir)
+		}
+		return regions, nil
+	}
+
 	// Discover all regions from the public pricing region index (no credentials needed)
-	regions, err := FetchRegionList(ctx)
+	regions, err := FetchRegionList(ctx, opts.Cache)
 	if err != nil {
 		// Fallback: if region index fails, use just the home region
 		if homeRegion != "" {
@@ -149,19 +276,28 @@ func resolveTargetRegions(ctx context.Context, targetRegions string, homeRegion
 }
 
 // generateForEngine runs the full generation pipeline for a single engine and returns
-// an InstanceTypes map with plain (non-prefixed) keys.
-func generateForEngine(ctx context.Context, cfg aws.Config, engine string, homeRegion string, targetRegions []string, status func(string)) (types.InstanceTypes, error) {
+// an InstanceTypes map with plain (non-prefixed) keys, plus the Aurora storage rates
+// and bulk pricing publication date of each region. Classes are marked previous
+// generation from the pricing files and the eol table (see loadEndOfLife).
+func generateForEngine(ctx context.Context, cfg aws.Config, opts GenerateOptions, engine string, targetRegions []string, eol map[string]string, status func(string)) (types.InstanceTypes, types.StoragePricing, map[string]string, error) {
+	homeRegion := opts.Region
+
 	// Run two independent tasks in parallel:
 	// 1. Fetch bulk JSON data for all target regions (hardware specs + pricing + availability)
-	// 2. Fetch engine version info from DescribeOrderableDBInstanceOptions (home region)
+	// 2. Fetch engine version info from DescribeOrderableDBInstanceOptions in each
+	//    target region, or read it from opts.OrderableFile (skipped when generating
+	//    from opts.PricingDir without one)
 
 	type bulkResult struct {
 		regionData map[string]map[string]BulkInstanceInfo // region -> instance type -> info
+		storage    types.StoragePricing
+		published  map[string]string
 		err        error
 	}
 
 	type orderableResult struct {
 		classInfo map[string]*orderableClassInfo
+		regions   map[string]bool // regions with orderable data
 		err       error
 	}
 
@@ -171,15 +307,32 @@ func generateForEngine(ctx context.Context, cfg aws.Config, engine string, homeR
 	// Task 1: Fetch bulk JSON for all regions
 	go func() {
 		status(fmt.Sprintf("[%s] Fetching pricing data across %d regions...", engine, len(targetRegions)))
-		data, err := fetchMultiRegionData(ctx, engine, targetRegions, status)
-		bulkCh <- bulkResult{regionData: data, err: err}
+		data, storage, published, err := fetchMultiRegionData(ctx, opts, engine, targetRegions, status)
+		bulkCh <- bulkResult{regionData: data, storage: storage, published: published, err: err}
 	}()
 
 	// Task 2: Fetch engine version info from DescribeOrderableDBInstanceOptions
 	go func() {
-		status(fmt.Sprintf("[%s] Collecting engine version support from DescribeOrderableDBInstanceOptions...", engine))
-		info, err := listOrderableClassInfo(ctx, cfg, engine)
-		orderableCh <- orderableResult{classInfo: info, err: err}
+		var (
+			options []orderableOption
+			err     error
+		)
+		if opts.OrderableFile != "" {
+			status(fmt.Sprintf("[%s] Reading engine version support from %s...", engine, opts.OrderableFile))
+			options, err = readOrderableFiles(opts.OrderableFile, engine, homeRegion)
+		} else if opts.PricingDir != "" {
+			orderableCh <- orderableResult{}
+			return
+		} else {
+			status(fmt.Sprintf("[%s] Collecting engine version support from DescribeOrderableDBInstanceOptions across %d regions...", engine, len(targetRegions)))
+			options, err = listRegionalOrderableOptions(ctx, cfg, engine, targetRegions, status)
+		}
+		if err != nil {
+			orderableCh <- orderableResult{err: err}
+			return
+		}
+		classInfo, regions := orderableClassInfoFrom(options)
+		orderableCh <- orderableResult{classInfo: classInfo, regions: regions}
 	}()
 
 	// Wait for both to complete
@@ -190,12 +343,14 @@ func generateForEngine(ctx context.Context, cfg aws.Config, engine string, homeR
 		status(fmt.Sprintf("[%s] Warning: multi-region bulk fetch had errors: %v", engine, bulkRes.err))
 	}
 	if orderableRes.err != nil {
-		status(fmt.Sprintf("[%s] Warning: DescribeOrderableDBInstanceOptions failed: %v", engine, orderableRes.err))
-		// Non-fatal: we just won't have MinEngineVersion data
+		status(fmt.Sprintf("[%s] Warning: orderable instance options unavailable: %v", engine, orderableRes.err))
+		// Non-fatal: we just won't have engine version data
 	}
 
 	regionData := bulkRes.regionData
 	classInfo := orderableRes.classInfo
+	orderableRegions := orderableRes.regions
+	notOrderable := 0
 
 	// Discover the union of all instance classes seen across all regions
 	allClassesSet := make(map[string]bool)
@@ -214,7 +369,7 @@ func generateForEngine(ctx context.Context, cfg aws.Config, engine string, homeR
 	status(fmt.Sprintf("[%s] Found %d unique instance classes across %d regions", engine, len(allClasses), len(regionData)))
 
 	if len(allClasses) == 0 {
-		return nil, fmt.Errorf("no instance classes found for engine %s across target regions", engine)
+		return nil, nil, nil, fmt.Errorf("no instance classes found for engine %s across target regions", engine)
 	}
 
 	// Build instance properties
@@ -225,11 +380,13 @@ func generateForEngine(ctx context.Context, cfg aws.Config, engine string, homeR
 		// Take hardware specs from the first region that has this class
 		var vcpu, mem int64
 		var maxBandwidth *int64
+		var previousGen bool
 		for _, regionInstances := range regionData {
 			if info, ok := regionInstances[cls]; ok {
 				vcpu = info.VCPUs
 				mem = info.MemoryGiB
 				maxBandwidth = info.MaxBandwidthMbps
+				previousGen = info.PreviousGeneration
 				break
 			}
 		}
@@ -241,14 +398,53 @@ func generateForEngine(ctx context.Context, cfg aws.Config, engine string, homeR
 
 		// Build pricing map: presence of region key = available in that region
 		pricing := make(map[string]float64)
+		var ioOptimizedPricing map[string]float64
+		var reservedPricing map[string]map[types.PricingModel]float64
+		var engineVersions map[string][]string
+		dropped := 0
 		for _, region := range targetRegions {
 			if regionInstances, ok := regionData[region]; ok {
 				if info, ok := regionInstances[cls]; ok && info.Price > 0 {
+					// Priced but not orderable for this engine in the region
+					var versions []string
+					orderable := false
+					if ci, ok := classInfo[cls]; ok {
+						versions, orderable = ci.regionVersions[region]
+					}
+					if orderableRegions[region] && !orderable {
+						dropped++
+						continue
+					}
+					if len(versions) > 0 {
+						if engineVersions == nil {
+							engineVersions = make(map[string][]string)
+						}
+						engineVersions[region] = versions
+					}
+
 					pricing[region] = info.Price
+					if info.IOOptimizedPrice > 0 {
+						if ioOptimizedPricing == nil {
+							ioOptimizedPricing = make(map[string]float64)
+						}
+						ioOptimizedPricing[region] = info.IOOptimizedPrice
+					}
+					if len(info.ReservedPrices) > 0 {
+						if reservedPricing == nil {
+							reservedPricing = make(map[string]map[types.PricingModel]float64)
+						}
+						reservedPricing[region] = info.ReservedPrices
+					}
 				}
 			}
 		}
 
+		notOrderable += dropped
+		if len(pricing) == 0 && dropped > 0 {
+			// Not orderable in any region it is priced in
+			continue
+		}
+
 		// Compute min engine version from DescribeOrderableDBInstanceOptions data
 		minVersion := ""
 		if classInfo != nil {
@@ -258,11 +454,19 @@ func generateForEngine(ctx context.Context, cfg aws.Config, engine string, homeR
 		}
 
 		props := types.InstanceProperties{
-			Vcpu:             vcpu,
-			Mem:              mem,
-			MaxBandwidth:     maxBandwidth,
-			Pricing:          pricing,
-			MinEngineVersion: minVersion,
+			Vcpu:               vcpu,
+			Mem:                mem,
+			MaxBandwidth:       maxBandwidth,
+			Pricing:            pricing,
+			MinEngineVersion:   minVersion,
+			ReservedPricing:    reservedPricing,
+			IOOptimizedPricing: ioOptimizedPricing,
+			EngineVersions:     engineVersions,
+			PreviousGeneration: previousGen,
+		}
+		if date, listed := endOfLife(eol, cls); listed {
+			props.PreviousGeneration = true
+			props.EndOfLife = date
 		}
 
 		// Set StdPrice from the home region for backward compatibility
@@ -276,20 +480,68 @@ func generateForEngine(ctx context.Context, cfg aws.Config, engine string, homeR
 		instanceTypes[cls] = props
 	}
 
+	if notOrderable > 0 {
+		status(fmt.Sprintf("[%s] Dropped %d priced class/region pairs not orderable for %s", engine, notOrderable, engine))
+	}
+
 	// Build up/down linked lists within each family
 	status(fmt.Sprintf("[%s] Linking instance families...", engine))
 	buildFamilyLinks(instanceTypes)
 
 	status(fmt.Sprintf("[%s] Generated %d instance types", engine, len(instanceTypes)))
-	return instanceTypes, nil
+	return instanceTypes, bulkRes.storage, bulkRes.published, nil
 }
 
-// listOrderableClassInfo calls DescribeOrderableDBInstanceOptions and returns
-// per-class info including all supported engine versions.
-func listOrderableClassInfo(ctx context.Context, cfg aws.Config, engine string) (map[string]*orderableClassInfo, error) {
-	client := awsRds.NewFromConfig(cfg)
+// listRegionalOrderableOptions lists the orderable options of engine in each of
+// regions in parallel. Regions that fail (e.g. not enabled for the account) are
+// reported through status and left out; it fails only when every region does.
+func listRegionalOrderableOptions(ctx context.Context, cfg aws.Config, engine string, regions []string, status func(string)) ([]orderableOption, error) {
+	const concurrency = 10
+	sem := make(chan struct{}, concurrency)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		options  []orderableOption
+		firstErr error
+		failed   int
+	)
+	for _, region := range regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-	result := make(map[string]*orderableClassInfo)
+			regionOptions, err := listOrderableOptions(ctx, cfg, engine, region)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				status(fmt.Sprintf("[%s] Warning: DescribeOrderableDBInstanceOptions failed in %s: %v", engine, region, err))
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			options = append(options, regionOptions...)
+		}(region)
+	}
+	wg.Wait()
+
+	if len(regions) > 0 && failed == len(regions) {
+		return nil, firstErr
+	}
+	return options, nil
+}
+
+// listOrderableOptions calls DescribeOrderableDBInstanceOptions in region and
+// returns the instance class and engine version of every orderable option.
+func listOrderableOptions(ctx context.Context, cfg aws.Config, engine string, region string) ([]orderableOption, error) {
+	client := awsRds.NewFromConfig(cfg, func(o *awsRds.Options) {
+		o.Region = region
+	})
+
+	var options []orderableOption
 
 	paginator := awsRds.NewDescribeOrderableDBInstanceOptionsPaginator(client, &awsRds.DescribeOrderableDBInstanceOptionsInput{
 		Engine: aws.String(engine),
@@ -305,38 +557,70 @@ func listOrderableClassInfo(ctx context.Context, cfg aws.Config, engine string)
 			if opt.DBInstanceClass == nil {
 				continue
 			}
-			cls := *opt.DBInstanceClass
+			options = append(options, orderableOption{
+				Engine:          engine,
+				EngineVersion:   aws.ToString(opt.EngineVersion),
+				DBInstanceClass: *opt.DBInstanceClass,
+				Region:          region,
+			})
+		}
+	}
 
-			if _, ok := result[cls]; !ok {
-				result[cls] = &orderableClassInfo{}
-			}
+	return options, nil
+}
 
-			// Collect engine version if available
-			if opt.EngineVersion != nil {
-				result[cls].engineVersions = append(result[cls].engineVersions, *opt.EngineVersion)
+// orderableClassInfoFrom groups orderable options into per-class info including all
+// supported engine versions, overall and per region. It also returns the regions
+// the options cover.
+func orderableClassInfoFrom(options []orderableOption) (map[string]*orderableClassInfo, map[string]bool) {
+	result := make(map[string]*orderableClassInfo)
+	regions := make(map[string]bool)
+
+	for _, opt := range options {
+		cls := opt.DBInstanceClass
+		if _, ok := result[cls]; !ok {
+			result[cls] = &orderableClassInfo{regionVersions: make(map[string][]string)}
+		}
+		regions[opt.Region] = true
+
+		// Collect engine version if available; the region is recorded either way
+		versions := result[cls].regionVersions[opt.Region]
+		if opt.EngineVersion != "" {
+			result[cls].engineVersions = append(result[cls].engineVersions, opt.EngineVersion)
+			if minor := util.EngineMinorVersion(opt.EngineVersion); !slices.Contains(versions, minor) {
+				versions = append(versions, minor)
 			}
 		}
+		result[cls].regionVersions[opt.Region] = versions
+	}
+
+	for _, info := range result {
+		for _, versions := range info.regionVersions {
+			slices.SortFunc(versions, util.CompareVersions)
+		}
 	}
 
-	return result, nil
+	return result, regions
 }
 
 // regionResult holds the output of a per-region bulk JSON fetch.
 type regionResult struct {
 	region string
-	data   map[string]BulkInstanceInfo
+	data   *BulkRegionData
 	err    error
 }
 
 // fetchMultiRegionData fetches bulk pricing JSON data across all target regions
 // in parallel with a concurrency limit. Each region's bulk JSON provides hardware
-// specs, pricing, and availability in a single download.
+// specs, pricing, and availability in a single download (or local file when
+// opts.PricingDir is set).
 func fetchMultiRegionData(
 	ctx context.Context,
+	opts GenerateOptions,
 	engine string,
 	targetRegions []string,
 	status func(string),
-) (map[string]map[string]BulkInstanceInfo, error) {
+) (map[string]map[string]BulkInstanceInfo, types.StoragePricing, map[string]string, error) {
 
 	const concurrency = 10
 
@@ -351,15 +635,23 @@ func fetchMultiRegionData(
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			status(fmt.Sprintf("[%s] Downloading bulk pricing for %s...", engine, region))
-
-			data, err := FetchBulkInstanceData(ctx, engine, region)
+			var (
+				data *BulkRegionData
+				err  error
+			)
+			if opts.PricingDir != "" {
+				status(fmt.Sprintf("[%s] Reading bulk pricing for %s...", engine, region))
+				data, err = ReadBulkInstanceData(PricingFilePath(opts.PricingDir, region), engine, region)
+			} else {
+				status(fmt.Sprintf("[%s] Downloading bulk pricing for %s...", engine, region))
+				data, err = FetchBulkInstanceData(ctx, opts.Cache, engine, region)
+			}
 			if err != nil {
 				results <- regionResult{region: region, err: fmt.Errorf("bulk data for %s: %w", region, err)}
 				return
 			}
 
-			status(fmt.Sprintf("[%s] Got %d instance types for %s", engine, len(data), region))
+			status(fmt.Sprintf("[%s] Got %d instance types for %s", engine, len(data.Instances), region))
 			results <- regionResult{
 				region: region,
 				data:   data,
@@ -375,6 +667,8 @@ func fetchMultiRegionData(
 
 	// Collect results
 	regionData := make(map[string]map[string]BulkInstanceInfo)
+	storagePricing := make(types.StoragePricing)
+	published := make(map[string]string)
 	var firstErr error
 
 	for res := range results {
@@ -385,10 +679,16 @@ func fetchMultiRegionData(
 			}
 			continue
 		}
-		regionData[res.region] = res.data
+		regionData[res.region] = res.data.Instances
+		if res.data.Storage != nil {
+			storagePricing[res.region] = *res.data.Storage
+		}
+		if res.data.PublicationDate != "" {
+			published[res.region] = res.data.PublicationDate
+		}
 	}
 
-	return regionData, firstErr
+	return regionData, storagePricing, published, firstErr
 }
 
 // computeMinEngineVersion finds the minimum engine version from a list of version strings.
@@ -0,0 +1,252 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/luneo7/rds-right-size/internal/util"
+)
+
+// OrderableFileName is the orderable options dump DownloadInputs writes to the
+// pricing directory, and GenerateInstanceTypes reads from it by default.
+const OrderableFileName = "orderable_options.json"
+
+// orderableOption is one DescribeOrderableDBInstanceOptions entry, reduced to the
+// fields the generator uses.
+type orderableOption struct {
+	Engine          string
+	EngineVersion   string
+	DBInstanceClass string
+	// Region is where the option is orderable. Dumps saved with the AWS CLI have
+	// none; there it is taken from AvailabilityZones, or else the home region.
+	Region            string          `json:",omitempty"`
+	AvailabilityZones []orderableZone `json:",omitempty"`
+}
+
+type orderableZone struct {
+	Name string
+}
+
+// zoneRegionRegex matches the region part of an availability zone name,
+// e.g. "us-east-1" of "us-east-1a" or "us-west-2" of "us-west-2-lax-1a".
+var zoneRegionRegex = regexp.MustCompile(`^[a-z]{2}(?:-[a-z]+)+-\d+`)
+
+// orderableDump is the saved form of DescribeOrderableDBInstanceOptions results,
+// the same shape as the AWS CLI's JSON output:
+//
+//	aws rds describe-orderable-db-instance-options --engine aurora-mysql --output json
+type orderableDump struct {
+	OrderableDBInstanceOptions []orderableOption
+}
+
+// PricingFilePath returns where the bulk pricing JSON of region is kept in a
+// pricing directory.
+func PricingFilePath(dir, region string) string {
+	return filepath.Join(dir, region, "index.json")
+}
+
+// DownloadInputs fetches everything GenerateInstanceTypes needs from the network
+// for later offline generation: the bulk pricing file of each target region into
+// opts.PricingDir, and the orderable instance options of each engine into
+// opts.OrderableFile (default: OrderableFileName in opts.PricingDir).
+func DownloadInputs(ctx context.Context, cfg aws.Config, opts GenerateOptions) error {
+	status := func(msg string) {
+		if opts.OnStatus != nil {
+			opts.OnStatus(msg)
+		}
+	}
+
+	if opts.PricingDir == "" {
+		return fmt.Errorf("a pricing directory is required to download generation inputs")
+	}
+	orderableFile := opts.OrderableFile
+	if orderableFile == "" {
+		orderableFile = filepath.Join(opts.PricingDir, OrderableFileName)
+	}
+
+	engines := []string{opts.Engine}
+	if opts.Engine == "" || opts.Engine == "both" {
+		engines = []string{"aurora-mysql", "aurora-postgresql"}
+	}
+
+	// Regions come from the network, not from what the directory already holds
+	regionOpts := opts
+	regionOpts.PricingDir = ""
+	targetRegions, err := resolveTargetRegions(ctx, regionOpts, status)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target regions: %w", err)
+	}
+	status(fmt.Sprintf("Target regions (%d): %s", len(targetRegions), strings.Join(targetRegions, ", ")))
+
+	// Bulk pricing files hold every engine, so each region is downloaded once
+	const concurrency = 10
+	sem := make(chan struct{}, concurrency)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []string
+	)
+	for _, region := range targetRegions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			status(fmt.Sprintf("Downloading bulk pricing for %s...", region))
+			if err := downloadBulkPricing(ctx, opts, region); err != nil {
+				status(fmt.Sprintf("Warning: %v", err))
+				mu.Lock()
+				failed = append(failed, region)
+				mu.Unlock()
+				return
+			}
+			status(fmt.Sprintf("Saved bulk pricing for %s", region))
+		}(region)
+	}
+	wg.Wait()
+
+	if len(failed) == len(targetRegions) {
+		return fmt.Errorf("failed to download bulk pricing for every target region")
+	}
+
+	var downloaded []string
+	for _, region := range targetRegions {
+		if !slices.Contains(failed, region) {
+			downloaded = append(downloaded, region)
+		}
+	}
+
+	// Like generation, missing orderable options only lose engine version data
+	var dump orderableDump
+	for _, engine := range engines {
+		status(fmt.Sprintf("[%s] Collecting orderable options from DescribeOrderableDBInstanceOptions across %d regions...", engine, len(downloaded)))
+		options, err := listRegionalOrderableOptions(ctx, cfg, engine, downloaded, status)
+		if err != nil {
+			status(fmt.Sprintf("[%s] Warning: DescribeOrderableDBInstanceOptions failed: %v", engine, err))
+			continue
+		}
+		dump.OrderableDBInstanceOptions = append(dump.OrderableDBInstanceOptions, options...)
+	}
+	if len(dump.OrderableDBInstanceOptions) > 0 {
+		data, err := json.MarshalIndent(dump, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal orderable options JSON: %w", err)
+		}
+		if err := os.WriteFile(orderableFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", orderableFile, err)
+		}
+		status(fmt.Sprintf("Written %d orderable options to %s", len(dump.OrderableDBInstanceOptions), orderableFile))
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		status(fmt.Sprintf("Warning: bulk pricing missing for %s", strings.Join(failed, ", ")))
+	}
+	status(fmt.Sprintf("Done! Saved generation inputs for %d regions to %s", len(targetRegions)-len(failed), opts.PricingDir))
+	return nil
+}
+
+// downloadBulkPricing saves the bulk pricing JSON of region into opts.PricingDir.
+// The file is written under a temporary name and renamed, so an interrupted
+// download never leaves a truncated file behind.
+func downloadBulkPricing(ctx context.Context, opts GenerateOptions, region string) error {
+	path := PricingFilePath(opts.PricingDir, region)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", region, err)
+	}
+
+	body, err := opts.Cache.Open(ctx, BulkPricingURL(region))
+	if err != nil {
+		return fmt.Errorf("failed to download bulk pricing for %s: %w", region, err)
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "index.*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file for %s: %w", region, err)
+	}
+	_, copyErr := io.Copy(tmp, body)
+	closeErr := tmp.Close()
+	if copyErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		if copyErr != nil {
+			return fmt.Errorf("failed to download bulk pricing for %s: %w", region, copyErr)
+		}
+		return fmt.Errorf("failed to write bulk pricing for %s: %w", region, closeErr)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write bulk pricing for %s: %w", region, err)
+	}
+	return nil
+}
+
+// localPricingRegions lists the regions with a bulk pricing file in dir, sorted.
+func localPricingRegions(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing directory %s: %w", dir, err)
+	}
+
+	var regions []string
+	for _, entry := range entries {
+		if entry.IsDir() && fileExists(PricingFilePath(dir, entry.Name())) {
+			regions = append(regions, entry.Name())
+		}
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// readOrderableFiles reads the orderable options of engine from one or more
+// comma-separated dump files. Options without a region are assigned the region
+// of their first availability zone, or homeRegion.
+func readOrderableFiles(paths string, engine string, homeRegion string) ([]orderableOption, error) {
+	var options []orderableOption
+	for _, path := range util.SplitList(paths) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read orderable options file %s: %w", path, err)
+		}
+
+		var dump orderableDump
+		if err := json.Unmarshal(data, &dump); err != nil {
+			return nil, fmt.Errorf("failed to parse orderable options JSON from %s: %w", path, err)
+		}
+
+		for _, opt := range dump.OrderableDBInstanceOptions {
+			if opt.Engine != engine || opt.DBInstanceClass == "" {
+				continue
+			}
+			if opt.Region == "" && len(opt.AvailabilityZones) > 0 {
+				opt.Region = zoneRegionRegex.FindString(opt.AvailabilityZones[0].Name)
+			}
+			if opt.Region == "" {
+				opt.Region = homeRegion
+			}
+			opt.AvailabilityZones = nil
+			options = append(options, opt)
+		}
+	}
+
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no %s orderable options in %s", engine, paths)
+	}
+	return options, nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"io"
+	"os"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/luneo7/rds-right-size/internal/cache"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
 )
 
 // BulkInstanceInfo holds hardware specs and pricing extracted from the public AWS bulk pricing JSON.
@@ -17,32 +21,59 @@ type BulkInstanceInfo struct {
 	VCPUs            int64
 	MemoryGiB        int64
 	MaxBandwidthMbps *int64
+	// ReservedPrices maps reserved pricing models to effective hourly rates.
+	ReservedPrices map[types.PricingModel]float64
+	// IOOptimizedPrice is the on-demand hourly rate for clusters using
+	// Aurora I/O-Optimized storage (0 when not offered).
+	IOOptimizedPrice float64
+	// PreviousGeneration is set when the pricing lists the class as not current generation.
+	PreviousGeneration bool
+}
+
+// BulkRegionData is what generation takes from one region's bulk pricing JSON.
+type BulkRegionData struct {
+	Instances map[string]BulkInstanceInfo
+	// Storage holds the region's Aurora storage and I/O rates (nil when it lists none).
+	Storage *types.StoragePrices
+	// PublicationDate is the publicationDate of the bulk pricing file.
+	PublicationDate string
 }
 
 // Bulk pricing JSON types
 type bulkPricingResponse struct {
-	Products map[string]bulkProduct `json:"products"`
-	Terms    struct {
+	PublicationDate string                 `json:"publicationDate"`
+	Products        map[string]bulkProduct `json:"products"`
+	Terms           struct {
 		OnDemand map[string]map[string]bulkTerm `json:"OnDemand"`
+		Reserved map[string]map[string]bulkTerm `json:"Reserved"`
 	} `json:"terms"`
 }
 
 type bulkProduct struct {
-	Attributes struct {
+	ProductFamily string `json:"productFamily"`
+	Attributes    struct {
 		InstanceType       string `json:"instanceType"`
 		DatabaseEngine     string `json:"databaseEngine"`
 		DeploymentOption   string `json:"deploymentOption"`
 		Storage            string `json:"storage"`
+		UsageType          string `json:"usagetype"`
 		VCPU               string `json:"vcpu"`
 		Memory             string `json:"memory"`
 		NetworkPerformance string `json:"networkPerformance"`
+		CurrentGeneration  string `json:"currentGeneration"`
 	} `json:"attributes"`
 }
 
 type bulkTerm struct {
 	PriceDimensions map[string]struct {
+		Unit         string            `json:"unit"`
 		PricePerUnit map[string]string `json:"pricePerUnit"`
 	} `json:"priceDimensions"`
+	TermAttributes struct {
+		LeaseContractLength string `json:"LeaseContractLength"`
+		OfferingClass       string `json:"OfferingClass"`
+		PurchaseOption      string `json:"PurchaseOption"`
+	} `json:"termAttributes"`
 }
 
 // regionIndexResponse represents the public AWS pricing region index.
@@ -52,35 +83,35 @@ type regionIndexResponse struct {
 	} `json:"regions"`
 }
 
+// hoursPerYear is used to amortize reserved upfront fees over the lease length.
+const hoursPerYear = 8760
+
 // bandwidthRegex extracts numeric bandwidth from strings like "Up to 10 Gigabit", "25 Gigabit"
 var bandwidthRegex = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*Gigabit`)
 
 // govOrLocalZoneRegex matches AWS GovCloud and local zone region codes.
 var govOrLocalZoneRegex = regexp.MustCompile(`^(us-gov-|.*-lax-|.*-wl1-)`)
 
-// FetchRegionList fetches the public AWS pricing region index for RDS and returns
-// a sorted list of region codes, excluding GovCloud and local zone regions.
-// This requires no AWS credentials.
-func FetchRegionList(ctx context.Context) ([]string, error) {
-	url := "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonRDS/current/region_index.json"
+// RegionIndexURL is the public AWS pricing region index for RDS.
+const RegionIndexURL = "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonRDS/current/region_index.json"
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// BulkPricingURL returns the public AWS bulk pricing file for RDS in a region.
+func BulkPricingURL(region string) string {
+	return fmt.Sprintf("https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonRDS/current/%s/index.json", region)
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// FetchRegionList fetches the public AWS pricing region index for RDS and returns
+// a sorted list of region codes, excluding GovCloud and local zone regions.
+// Downloads go through c when set. This requires no AWS credentials.
+func FetchRegionList(ctx context.Context, c *cache.Cache) ([]string, error) {
+	body, err := c.Open(ctx, RegionIndexURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch region index: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("region index returned HTTP %d", resp.StatusCode)
-	}
+	defer body.Close()
 
 	var index regionIndexResponse
-	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+	if err := json.NewDecoder(body).Decode(&index); err != nil {
 		return nil, fmt.Errorf("failed to parse region index: %w", err)
 	}
 
@@ -103,33 +134,66 @@ func FetchRegionList(ctx context.Context) ([]string, error) {
 
 // FetchBulkInstanceData downloads the public AWS bulk pricing JSON for the given
 // region and engine, and extracts hardware specs + on-demand pricing for all
-// matching Aurora instance types.
-// This requires no AWS credentials.
-func FetchBulkInstanceData(ctx context.Context, engine string, region string) (map[string]BulkInstanceInfo, error) {
-	url := fmt.Sprintf("https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonRDS/current/%s/index.json", region)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// matching Aurora instance types, along with the region's Aurora storage and I/O
+// rates and the file's publication date.
+// Downloads go through c when set. This requires no AWS credentials.
+func FetchBulkInstanceData(ctx context.Context, c *cache.Cache, engine string, region string) (*BulkRegionData, error) {
+	body, err := c.Open(ctx, BulkPricingURL(region))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to download bulk pricing for %s: %w", region, err)
 	}
+	defer body.Close()
+
+	// Without a cache the body is the HTTP response; spool it to a temporary file so
+	// the decoder can rewind it rather than buffer it in memory
+	seeker, ok := body.(io.ReadSeeker)
+	if !ok {
+		spool, err := os.CreateTemp("", "rds-bulk-pricing-*.json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary file for bulk pricing of %s: %w", region, err)
+		}
+		defer os.Remove(spool.Name())
+		defer spool.Close()
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download bulk pricing for %s: %w", region, err)
+		if _, err := io.Copy(spool, body); err != nil {
+			return nil, fmt.Errorf("failed to download bulk pricing for %s: %w", region, err)
+		}
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to rewind bulk pricing for %s: %w", region, err)
+		}
+		seeker = spool
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bulk pricing download for %s returned HTTP %d", region, resp.StatusCode)
+	return bulkInstanceData(seeker, engine, region)
+}
+
+// ReadBulkInstanceData is FetchBulkInstanceData for a bulk pricing JSON file
+// downloaded beforehand (see DownloadInputs).
+func ReadBulkInstanceData(path string, engine string, region string) (*BulkRegionData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bulk pricing for %s: %w", region, err)
 	}
+	defer f.Close()
+
+	return bulkInstanceData(f, engine, region)
+}
 
-	// Decode directly from the HTTP response body (streams, avoids full buffering)
-	var bulk bulkPricingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&bulk); err != nil {
+// bulkInstanceData extracts the instance types and storage rates of engine from a
+// region's bulk pricing JSON.
+func bulkInstanceData(body io.ReadSeeker, engine string, region string) (*BulkRegionData, error) {
+	databaseEngine := engineToPricingEngine(engine)
+
+	// Stream the file, keeping only this engine's products, the Aurora storage
+	// products and their terms; the full file is several hundred megabytes.
+	bulk, err := decodeBulkPricing(body, func(product bulkProduct) bool {
+		return strings.EqualFold(product.Attributes.DatabaseEngine, databaseEngine) || isAuroraStorageProduct(product)
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse bulk pricing JSON for %s: %w", region, err)
 	}
 
-	databaseEngine := engineToPricingEngine(engine)
+	storage := storagePrices(bulk)
 
 	// Phase 1: Find matching SKUs and extract hardware specs
 	type skuInfo struct {
@@ -137,6 +201,8 @@ func FetchBulkInstanceData(ctx context.Context, engine string, region string) (m
 		vcpus        int64
 		memoryGiB    int64
 		maxBandwidth *int64
+		ioOptimized  bool
+		previousGen  bool
 	}
 	skuMap := make(map[string]skuInfo)
 
@@ -149,7 +215,8 @@ func FetchBulkInstanceData(ctx context.Context, engine string, region string) (m
 		if attrs.DeploymentOption != "Single-AZ" {
 			continue
 		}
-		if attrs.Storage != "EBS Only" {
+		ioOptimized := isIOOptimizedProduct(attrs.Storage, attrs.UsageType)
+		if attrs.Storage != "EBS Only" && !ioOptimized {
 			continue
 		}
 		if !strings.HasPrefix(attrs.InstanceType, "db.") {
@@ -165,44 +232,193 @@ func FetchBulkInstanceData(ctx context.Context, engine string, region string) (m
 			vcpus:        vcpus,
 			memoryGiB:    memGiB,
 			maxBandwidth: bandwidth,
+			ioOptimized:  ioOptimized,
+			previousGen:  strings.EqualFold(attrs.CurrentGeneration, "No"),
 		}
 	}
 
-	// Phase 2: Extract on-demand prices from terms for matched SKUs
+	// Phase 2: Extract on-demand prices from terms for matched SKUs.
+	// I/O-Optimized SKUs only contribute their rate to the standard entry.
 	result := make(map[string]BulkInstanceInfo)
+	ioOptimizedPrices := make(map[string]float64)
 	for sku, info := range skuMap {
 		terms, ok := bulk.Terms.OnDemand[sku]
 		if !ok {
 			continue
 		}
 
-		var price float64
-		for _, term := range terms {
-			for _, dim := range term.PriceDimensions {
-				if usd, ok := dim.PricePerUnit["USD"]; ok {
-					p, err := strconv.ParseFloat(usd, 64)
-					if err == nil && p > 0 {
-						price = p
-						break
-					}
+		price := onDemandPrice(terms)
+		if price <= 0 {
+			continue
+		}
+
+		if info.ioOptimized {
+			ioOptimizedPrices[info.instanceType] = price
+			continue
+		}
+
+		result[info.instanceType] = BulkInstanceInfo{
+			Price:              price,
+			VCPUs:              info.vcpus,
+			MemoryGiB:          info.memoryGiB,
+			MaxBandwidthMbps:   info.maxBandwidth,
+			ReservedPrices:     reservedPrices(bulk.Terms.Reserved[sku]),
+			PreviousGeneration: info.previousGen,
+		}
+	}
+
+	for instanceType, price := range ioOptimizedPrices {
+		if info, ok := result[instanceType]; ok {
+			info.IOOptimizedPrice = price
+			result[instanceType] = info
+		}
+	}
+
+	return &BulkRegionData{Instances: result, Storage: storage, PublicationDate: bulk.PublicationDate}, nil
+}
+
+// storagePrices extracts the Aurora storage (GB-month) and I/O request rates from
+// the region's "Database Storage" and "System Operation" products, matched by
+// usage type (e.g. "USW2-Aurora:StorageUsage", "Aurora:IO-OptimizedStorageUsage",
+// "Aurora:StorageIOUsage"). Returns nil when no standard storage rate is found.
+func storagePrices(bulk *bulkPricingResponse) *types.StoragePrices {
+	var prices types.StoragePrices
+	for sku, product := range bulk.Products {
+		if !isAuroraStorageProduct(product) {
+			continue
+		}
+		usageType := product.Attributes.UsageType
+		operation := usageType[strings.Index(usageType, "Aurora:")+len("Aurora:"):]
+
+		price := onDemandPrice(bulk.Terms.OnDemand[sku])
+		if price <= 0 {
+			continue
+		}
+
+		switch {
+		case operation == "StorageUsage":
+			prices.StandardStorage = price
+		case operation == "StorageIOUsage":
+			prices.StandardIO = price * 1e6
+		case strings.HasSuffix(operation, "StorageUsage") && strings.Contains(operation, "Optimized"):
+			prices.IOOptimizedStorage = price
+		}
+	}
+	if prices.StandardStorage == 0 {
+		return nil
+	}
+	return &prices
+}
+
+// isAuroraStorageProduct reports whether a product is an Aurora storage or I/O
+// request rate ("Database Storage" or "System Operation" with an "Aurora:" usage type).
+func isAuroraStorageProduct(product bulkProduct) bool {
+	if product.ProductFamily != "Database Storage" && product.ProductFamily != "System Operation" {
+		return false
+	}
+	return strings.Contains(product.Attributes.UsageType, "Aurora:")
+}
+
+// onDemandPrice returns the first positive USD hourly rate among a SKU's on-demand terms.
+func onDemandPrice(terms map[string]bulkTerm) float64 {
+	for _, term := range terms {
+		for _, dim := range term.PriceDimensions {
+			if usd, ok := dim.PricePerUnit["USD"]; ok {
+				p, err := strconv.ParseFloat(usd, 64)
+				if err == nil && p > 0 {
+					return p
 				}
 			}
-			if price > 0 {
-				break
-			}
 		}
+	}
+	return 0
+}
+
+// isIOOptimizedProduct reports whether a product is the Aurora I/O-Optimized
+// variant of an instance class (storage "Aurora IO Optimization Mode",
+// usage type "...InstanceUsageIOOptimized:db.*").
+func isIOOptimizedProduct(storage, usageType string) bool {
+	return strings.Contains(storage, "IO Optimization") || strings.Contains(usageType, "IOOptimized")
+}
 
-		if price > 0 {
-			result[info.instanceType] = BulkInstanceInfo{
-				Price:            price,
-				VCPUs:            info.vcpus,
-				MemoryGiB:        info.memoryGiB,
-				MaxBandwidthMbps: info.maxBandwidth,
+// reservedPrices computes the effective hourly rate for each reserved offering of a SKU.
+// The upfront fee (unit "Quantity") is amortized over the lease length and added to
+// the hourly charge (unit "Hrs"). Returns nil when the SKU has no reserved offerings.
+func reservedPrices(terms map[string]bulkTerm) map[types.PricingModel]float64 {
+	var result map[types.PricingModel]float64
+
+	for _, term := range terms {
+		model, hours, ok := reservedPricingModel(term)
+		if !ok {
+			continue
+		}
+
+		var upfront, hourly float64
+		for _, dim := range term.PriceDimensions {
+			usd, ok := dim.PricePerUnit["USD"]
+			if !ok {
+				continue
+			}
+			p, err := strconv.ParseFloat(usd, 64)
+			if err != nil {
+				continue
+			}
+			switch dim.Unit {
+			case "Quantity":
+				upfront += p
+			case "Hrs":
+				hourly += p
 			}
 		}
+
+		effective := hourly + upfront/hours
+		if effective <= 0 {
+			continue
+		}
+		if result == nil {
+			result = make(map[types.PricingModel]float64)
+		}
+		result[model] = effective
 	}
 
-	return result, nil
+	return result
+}
+
+// reservedPricingModel maps a reserved term's attributes to a pricing model and
+// the number of hours in its lease. Convertible offerings are ignored.
+func reservedPricingModel(term bulkTerm) (types.PricingModel, float64, bool) {
+	attrs := term.TermAttributes
+	if attrs.OfferingClass != "" && !strings.EqualFold(attrs.OfferingClass, "standard") {
+		return "", 0, false
+	}
+
+	var years int
+	switch attrs.LeaseContractLength {
+	case "1yr":
+		years = 1
+	case "3yr":
+		years = 3
+	default:
+		return "", 0, false
+	}
+
+	var option string
+	switch attrs.PurchaseOption {
+	case "No Upfront":
+		option = "no-upfront"
+	case "Partial Upfront":
+		option = "partial-upfront"
+	case "All Upfront":
+		option = "all-upfront"
+	default:
+		return "", 0, false
+	}
+
+	model, err := types.ParsePricingModel(fmt.Sprintf("ri-%dy-%s", years, option))
+	if err != nil {
+		return "", 0, false
+	}
+	return model, float64(years) * hoursPerYear, true
 }
 
 // parseVCPU parses a vcpu string like "2" to int64.
@@ -0,0 +1,63 @@
+package org
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+type Organizations struct {
+	orgClient *organizations.Client
+}
+
+func NewOrganizations(awsConfig *aws.Config) *Organizations {
+	return &Organizations{
+		orgClient: organizations.NewFromConfig(*awsConfig),
+	}
+}
+
+// GetActiveAccounts returns the IDs of all active accounts under the given
+// organizational unit (or root), including accounts in nested OUs.
+func (o *Organizations) GetActiveAccounts(ctx context.Context, parentId string) ([]string, error) {
+	var accountIds []string
+
+	accounts := organizations.NewListAccountsForParentPaginator(o.orgClient, &organizations.ListAccountsForParentInput{
+		ParentId: &parentId,
+	})
+	for accounts.HasMorePages() {
+		output, err := accounts.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range output.Accounts {
+			if account.Id == nil || account.State != orgTypes.AccountStateActive {
+				continue
+			}
+			accountIds = append(accountIds, *account.Id)
+		}
+	}
+
+	children := organizations.NewListOrganizationalUnitsForParentPaginator(o.orgClient, &organizations.ListOrganizationalUnitsForParentInput{
+		ParentId: &parentId,
+	})
+	for children.HasMorePages() {
+		output, err := children.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, ou := range output.OrganizationalUnits {
+			if ou.Id == nil {
+				continue
+			}
+			nested, err := o.GetActiveAccounts(ctx, *ou.Id)
+			if err != nil {
+				return nil, err
+			}
+			accountIds = append(accountIds, nested...)
+		}
+	}
+
+	return accountIds, nil
+}
@@ -0,0 +1,67 @@
+package rds_right_size
+
+import (
+	"fmt"
+
+	"github.com/luneo7/rds-right-size/internal/cur"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+)
+
+// CheckCURCalibration returns an error when prices are not public on-demand list
+// prices. The CUR discount ratio is relative to on-demand list prices, so applying
+// it to reserved or overridden prices would count the discount twice.
+func CheckCURCalibration(pricingModel types.PricingModel, overrides *types.PricingOverrides) error {
+	if pricingModel != "" && pricingModel != types.OnDemand {
+		return fmt.Errorf("CUR calibration requires the %s pricing model, not %s", types.OnDemand, pricingModel)
+	}
+	if overrides != nil {
+		return fmt.Errorf("CUR calibration cannot be combined with pricing overrides")
+	}
+	return nil
+}
+
+// applyCURCalibration rescales the list-price cost difference of each
+// recommendation whose instance appears in the Cost and Usage Report by that
+// instance's effective discount ratio (billed cost over public on-demand cost), so
+// both the current and target costs reflect what is actually paid. The list-price
+// figure is kept in ListMonthlyPriceDiff. EffectiveMonthlyPriceDiff is left as is:
+// the ratio already includes the reservation discount of covered usage, which the
+// reserved coverage accounts for. Recommendations without CUR data are left
+// unchanged.
+func applyCURCalibration(recommendations []types.Recommendation, costs cur.Costs) {
+	for i := range recommendations {
+		rec := &recommendations[i]
+		if rec.DBInstanceArn == nil || rec.MonthlyApproximatePriceDiff == nil {
+			continue
+		}
+		cost, ok := costs[*rec.DBInstanceArn]
+		if !ok {
+			continue
+		}
+		ratio := cost.DiscountRatio()
+		if ratio <= 0 {
+			continue
+		}
+
+		rec.ListMonthlyPriceDiff = Float64(*rec.MonthlyApproximatePriceDiff)
+		rec.MonthlyApproximatePriceDiff = Float64(*rec.MonthlyApproximatePriceDiff * ratio)
+		rec.CURHourlyRate = Float64(cost.HourlyRate())
+		rec.CURDiscountRatio = Float64(ratio)
+		if rec.BlockedGenerationUpgrade != nil {
+			blocked := *rec.BlockedGenerationUpgrade
+			blocked.MonthlySavings *= ratio
+			rec.BlockedGenerationUpgrade = &blocked
+		}
+	}
+}
+
+// CURCalibratedCount returns how many recommendations were calibrated against the CUR.
+func CURCalibratedCount(recommendations []types.Recommendation) int {
+	count := 0
+	for _, rec := range recommendations {
+		if rec.CURDiscountRatio != nil {
+			count++
+		}
+	}
+	return count
+}
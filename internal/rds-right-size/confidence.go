@@ -0,0 +1,104 @@
+package rds_right_size
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
+)
+
+const (
+	// dataPointsPerDay is the number of 1-minute CloudWatch data points RDS
+	// publishes per day
+	dataPointsPerDay = 24 * 60
+
+	// minCoverage is the share of the lookback window the metrics must cover
+	// before coverage lowers the confidence
+	minCoverage = 0.95
+
+	// moderateCPUVariation and highCPUVariation are coefficients of variation of
+	// hourly CPU above which the lookback statistic represents the load poorly
+	moderateCPUVariation = 0.5
+	highCPUVariation     = 1.0
+)
+
+// confidence is a recommendation's confidence score (0-100) and the reasons it
+// was lowered.
+type confidence struct {
+	score   float64
+	reasons []string
+}
+
+// confidence scores how well the metrics of an instance support a recommendation.
+// The score starts at 100 and is scaled down by each weakness of the data: the
+// share of the lookback window covered (less after an instance class change), too
+// few data points, highly variable CPU, an instance younger than the window and
+// metrics projected onto a pending class (runningClass is the class they were
+// measured on).
+func (r *RDSRightSize) confidence(instance *rdsTypes.Instance, metrics *cwTypes.Metrics, runningClass string, now time.Time) confidence {
+	score := 1.0
+	var reasons []string
+	lookback := time.Duration(r.period) * 24 * time.Hour
+
+	coverage := math.Min(float64(metrics.DataPoints)/float64(r.period*dataPointsPerDay), 1)
+	if coverage < minCoverage {
+		score *= coverage
+		reasons = append(reasons, fmt.Sprintf("Metrics cover %.0f%% of the %d-day lookback", coverage*100, r.period))
+	}
+
+	if metrics.DataPoints < dataPointsPerDay {
+		score *= 0.5
+		reasons = append(reasons, fmt.Sprintf("Only %d data points, less than a day of metrics", metrics.DataPoints))
+	}
+
+	if v := metrics.CPUVariation; v != nil {
+		if *v >= highCPUVariation {
+			score *= 0.7
+			reasons = append(reasons, fmt.Sprintf("CPU varies widely hour to hour (coefficient of variation %.2f)", *v))
+		} else if *v >= moderateCPUVariation {
+			score *= 0.85
+			reasons = append(reasons, fmt.Sprintf("CPU varies hour to hour (coefficient of variation %.2f)", *v))
+		}
+	}
+
+	// A young instance also has low coverage, so its age only lowers the score slightly
+	if instance.InstanceCreateTime != nil {
+		if age := now.Sub(*instance.InstanceCreateTime); age < lookback {
+			score *= 0.9
+			reasons = append(reasons, fmt.Sprintf("Instance was created %.0f days ago, within the lookback", age.Hours()/24))
+		}
+	}
+
+	if runningClass != "" {
+		score *= 0.9
+		reasons = append(reasons, fmt.Sprintf("Metrics measured on %s were projected onto the pending class %s", runningClass, *instance.DBInstanceClass))
+	}
+
+	// The metrics were restricted to after the change, which the coverage accounts for
+	if instance.LastClassChange != nil && now.Sub(*instance.LastClassChange) < lookback {
+		reasons = append(reasons, fmt.Sprintf("Metrics restricted to the %.0f days since the instance class changed on %s",
+			now.Sub(*instance.LastClassChange).Hours()/24, instance.LastClassChange.UTC().Format("2006-01-02")))
+	}
+
+	return confidence{
+		score:   math.Round(score * 100),
+		reasons: reasons,
+	}
+}
+
+// filterByConfidence drops instance recommendations scored below minConfidence.
+// Recommendations without a score (cluster storage, Unavailable instances) and
+// Skipped instances are kept.
+func filterByConfidence(recommendations []types.Recommendation, minConfidence float64) []types.Recommendation {
+	filtered := recommendations[:0]
+	for _, rec := range recommendations {
+		if rec.Confidence != nil && *rec.Confidence < minConfidence && rec.Recommendation != types.Skipped {
+			continue
+		}
+		filtered = append(filtered, rec)
+	}
+	return filtered
+}
@@ -0,0 +1,49 @@
+package rds_right_size
+
+import (
+	"github.com/luneo7/rds-right-size/internal/currency"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+)
+
+// applyCurrency converts the USD amounts of each recommendation to the reporting
+// currency and records the rate used. A nil currency leaves amounts in USD.
+func applyCurrency(recommendations []types.Recommendation, c *currency.Currency) {
+	if c == nil {
+		return
+	}
+
+	convert := func(v *float64) *float64 {
+		if v == nil {
+			return nil
+		}
+		return Float64(c.Convert(*v))
+	}
+	convertCost := func(cost types.StorageConfigurationCost) types.StorageConfigurationCost {
+		return types.StorageConfigurationCost{
+			Instances: c.Convert(cost.Instances),
+			Storage:   c.Convert(cost.Storage),
+			IO:        c.Convert(cost.IO),
+			Total:     c.Convert(cost.Total),
+		}
+	}
+
+	for i := range recommendations {
+		rec := &recommendations[i]
+		rec.MonthlyApproximatePriceDiff = convert(rec.MonthlyApproximatePriceDiff)
+		rec.ListMonthlyPriceDiff = convert(rec.ListMonthlyPriceDiff)
+		rec.CURHourlyRate = convert(rec.CURHourlyRate)
+		rec.EffectiveMonthlyPriceDiff = convert(rec.EffectiveMonthlyPriceDiff)
+		if rec.StorageCost != nil {
+			storageCost := *rec.StorageCost
+			storageCost.Standard = convertCost(storageCost.Standard)
+			storageCost.IOOptimized = convertCost(storageCost.IOOptimized)
+			rec.StorageCost = &storageCost
+		}
+		if rec.BlockedGenerationUpgrade != nil {
+			blocked := *rec.BlockedGenerationUpgrade
+			blocked.MonthlySavings = c.Convert(blocked.MonthlySavings)
+			rec.BlockedGenerationUpgrade = &blocked
+		}
+		rec.Currency = c
+	}
+}
@@ -0,0 +1,328 @@
+package rds_right_size
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/luneo7/rds-right-size/internal/currency"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+	"github.com/luneo7/rds-right-size/internal/util"
+)
+
+// stdPriceRegion labels StdPrice changes of files without per-region pricing.
+const stdPriceRegion = "stdPrice"
+
+// InstanceTypesDiff lists the differences between two instance types files.
+type InstanceTypesDiff struct {
+	// Old and New identify the compared files when set by the caller.
+	Old *types.InstanceTypesProvenance `json:"old,omitempty"`
+	New *types.InstanceTypesProvenance `json:"new,omitempty"`
+	// Currency is the currency of the prices after ConvertPrices (USD when nil).
+	Currency *currency.Currency `json:"currency,omitempty"`
+
+	Added                      []string                    `json:"added"`
+	Removed                    []string                    `json:"removed"`
+	PriceChanges               []PriceChange               `json:"priceChanges"`
+	AvailabilityChanges        []AvailabilityChange        `json:"availabilityChanges"`
+	EngineVersionChanges       []EngineVersionChange       `json:"engineVersionChanges"`
+	RegionEngineVersionChanges []RegionEngineVersionChange `json:"regionEngineVersionChanges"`
+	Relinks                    []Relink                    `json:"relinks"`
+}
+
+// PriceChange is an on-demand hourly price change of a class in a region.
+type PriceChange struct {
+	Key           string  `json:"key"`
+	Region        string  `json:"region"`
+	OldPrice      float64 `json:"oldPrice"`
+	NewPrice      float64 `json:"newPrice"`
+	ChangePercent float64 `json:"changePercent"`
+}
+
+// AvailabilityChange is a class becoming available or unavailable in a region.
+type AvailabilityChange struct {
+	Key       string `json:"key"`
+	Region    string `json:"region"`
+	Available bool   `json:"available"`
+}
+
+// EngineVersionChange is a change of a class's minimum engine version.
+type EngineVersionChange struct {
+	Key        string `json:"key"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+}
+
+// RegionEngineVersionChange lists the engine versions a class became orderable
+// or stopped being orderable with in a region.
+type RegionEngineVersionChange struct {
+	Key     string   `json:"key"`
+	Region  string   `json:"region"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// Relink is a change of a class's up or down pointer ("" for none).
+type Relink struct {
+	Key     string `json:"key"`
+	Pointer string `json:"pointer"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// Empty reports whether the files have no reported differences.
+func (d InstanceTypesDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.PriceChanges) == 0 &&
+		len(d.AvailabilityChanges) == 0 && len(d.EngineVersionChanges) == 0 &&
+		len(d.RegionEngineVersionChanges) == 0 && len(d.Relinks) == 0
+}
+
+// ConvertPrices converts the USD prices of the price changes to cur and records
+// it as the diff's currency. A nil cur leaves the prices in USD.
+func (d *InstanceTypesDiff) ConvertPrices(cur *currency.Currency) {
+	if cur == nil {
+		return
+	}
+	for i := range d.PriceChanges {
+		d.PriceChanges[i].OldPrice = cur.Convert(d.PriceChanges[i].OldPrice)
+		d.PriceChanges[i].NewPrice = cur.Convert(d.PriceChanges[i].NewPrice)
+	}
+	d.Currency = cur
+}
+
+// DiffInstanceTypes compares two instance types maps. Price changes smaller than
+// minChangePercent (in either direction) are left out; a price rising from zero
+// counts as +100%. Files without per-region pricing are compared on StdPrice.
+// Per-region EngineVersions are compared when either file lists them for a region.
+// All lists are sorted by key, then region.
+func DiffInstanceTypes(oldTypes, newTypes types.InstanceTypes, minChangePercent float64) InstanceTypesDiff {
+	diff := InstanceTypesDiff{
+		Added:                      []string{},
+		Removed:                    []string{},
+		PriceChanges:               []PriceChange{},
+		AvailabilityChanges:        []AvailabilityChange{},
+		EngineVersionChanges:       []EngineVersionChange{},
+		RegionEngineVersionChanges: []RegionEngineVersionChange{},
+		Relinks:                    []Relink{},
+	}
+
+	for key := range newTypes {
+		if _, ok := oldTypes[key]; !ok {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+	sort.Strings(diff.Added)
+
+	var common []string
+	for key := range oldTypes {
+		if _, ok := newTypes[key]; ok {
+			common = append(common, key)
+		} else {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	sort.Strings(diff.Removed)
+	sort.Strings(common)
+
+	for _, key := range common {
+		oldProps, newProps := oldTypes[key], newTypes[key]
+
+		oldPrices, newPrices := diffPrices(oldProps), diffPrices(newProps)
+		regions := make(map[string]bool)
+		for region := range oldPrices {
+			regions[region] = true
+		}
+		for region := range newPrices {
+			regions[region] = true
+		}
+		for _, region := range sortedRegions(regions) {
+			oldPrice, wasAvailable := oldPrices[region]
+			newPrice, isAvailable := newPrices[region]
+			if wasAvailable != isAvailable {
+				if region != stdPriceRegion {
+					diff.AvailabilityChanges = append(diff.AvailabilityChanges, AvailabilityChange{Key: key, Region: region, Available: isAvailable})
+				}
+				continue
+			}
+			if oldPrice == newPrice {
+				continue
+			}
+			change := 100.0
+			if oldPrice > 0 {
+				change = (newPrice - oldPrice) / oldPrice * 100
+			}
+			if math.Abs(change) < minChangePercent {
+				continue
+			}
+			diff.PriceChanges = append(diff.PriceChanges, PriceChange{
+				Key:           key,
+				Region:        region,
+				OldPrice:      oldPrice,
+				NewPrice:      newPrice,
+				ChangePercent: math.Round(change*100) / 100,
+			})
+		}
+
+		if oldProps.MinEngineVersion != newProps.MinEngineVersion {
+			diff.EngineVersionChanges = append(diff.EngineVersionChanges, EngineVersionChange{
+				Key:        key,
+				OldVersion: oldProps.MinEngineVersion,
+				NewVersion: newProps.MinEngineVersion,
+			})
+		}
+
+		diff.RegionEngineVersionChanges = append(diff.RegionEngineVersionChanges, diffEngineVersions(key, oldProps, newProps)...)
+
+		if oldUp, newUp := stringValue(oldProps.Up), stringValue(newProps.Up); oldUp != newUp {
+			diff.Relinks = append(diff.Relinks, Relink{Key: key, Pointer: "up", Old: oldUp, New: newUp})
+		}
+		if oldDown, newDown := stringValue(oldProps.Down), stringValue(newProps.Down); oldDown != newDown {
+			diff.Relinks = append(diff.Relinks, Relink{Key: key, Pointer: "down", Old: oldDown, New: newDown})
+		}
+	}
+
+	return diff
+}
+
+// diffPrices returns the on-demand prices compared by DiffInstanceTypes.
+func diffPrices(props types.InstanceProperties) map[string]float64 {
+	if props.Pricing != nil {
+		return props.Pricing
+	}
+	if props.StdPrice > 0 {
+		return map[string]float64{stdPriceRegion: props.StdPrice}
+	}
+	return nil
+}
+
+// diffEngineVersions compares the per-region EngineVersions of a class.
+func diffEngineVersions(key string, oldProps, newProps types.InstanceProperties) []RegionEngineVersionChange {
+	regions := make(map[string]bool)
+	for region := range oldProps.EngineVersions {
+		regions[region] = true
+	}
+	for region := range newProps.EngineVersions {
+		regions[region] = true
+	}
+
+	var changes []RegionEngineVersionChange
+	for _, region := range sortedRegions(regions) {
+		added := versionsNotIn(newProps.EngineVersions[region], oldProps.EngineVersions[region])
+		removed := versionsNotIn(oldProps.EngineVersions[region], newProps.EngineVersions[region])
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, RegionEngineVersionChange{Key: key, Region: region, Added: added, Removed: removed})
+		}
+	}
+	return changes
+}
+
+// versionsNotIn returns the versions of a missing from b, sorted by version.
+func versionsNotIn(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, v := range b {
+		in[v] = true
+	}
+	missing := []string{}
+	for _, v := range a {
+		if !in[v] {
+			missing = append(missing, v)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return util.CompareVersions(missing[i], missing[j]) < 0
+	})
+	return missing
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// WriteInstanceTypesDiff writes a human-readable summary of diff, with prices in
+// the diff's currency.
+func WriteInstanceTypesDiff(w io.Writer, diff InstanceTypesDiff) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	orNone := func(s string) string {
+		if s == "" {
+			return "(none)"
+		}
+		return s
+	}
+
+	if diff.Old != nil && diff.New != nil {
+		printf("--- %s\n+++ %s\n", diff.Old.Summary(), diff.New.Summary())
+	}
+	if label := diff.Currency.Label(); label != "" {
+		printf("Prices in %s\n", label)
+	}
+	if diff.Empty() {
+		printf("No differences\n")
+		return err
+	}
+
+	if len(diff.Added) > 0 {
+		printf("Added classes (%d):\n", len(diff.Added))
+		for _, key := range diff.Added {
+			printf("  + %s\n", key)
+		}
+	}
+	if len(diff.Removed) > 0 {
+		printf("Removed classes (%d):\n", len(diff.Removed))
+		for _, key := range diff.Removed {
+			printf("  - %s\n", key)
+		}
+	}
+	if len(diff.PriceChanges) > 0 {
+		printf("Price changes (%d):\n", len(diff.PriceChanges))
+		for _, c := range diff.PriceChanges {
+			printf("  %-32s %-16s %s -> %s (%+.1f%%)\n", c.Key, c.Region,
+				diff.Currency.FormatRate(c.OldPrice), diff.Currency.FormatRate(c.NewPrice), c.ChangePercent)
+		}
+	}
+	if len(diff.AvailabilityChanges) > 0 {
+		printf("Availability changes (%d):\n", len(diff.AvailabilityChanges))
+		for _, c := range diff.AvailabilityChanges {
+			change := "no longer available"
+			if c.Available {
+				change = "now available"
+			}
+			printf("  %-32s %-16s %s\n", c.Key, c.Region, change)
+		}
+	}
+	if len(diff.EngineVersionChanges) > 0 {
+		printf("Minimum engine version changes (%d):\n", len(diff.EngineVersionChanges))
+		for _, c := range diff.EngineVersionChanges {
+			printf("  %-32s %s -> %s\n", c.Key, orNone(c.OldVersion), orNone(c.NewVersion))
+		}
+	}
+	if len(diff.RegionEngineVersionChanges) > 0 {
+		printf("Engine version changes by region (%d):\n", len(diff.RegionEngineVersionChanges))
+		for _, c := range diff.RegionEngineVersionChanges {
+			var changes []string
+			for _, v := range c.Added {
+				changes = append(changes, "+"+v)
+			}
+			for _, v := range c.Removed {
+				changes = append(changes, "-"+v)
+			}
+			printf("  %-32s %-16s %s\n", c.Key, c.Region, strings.Join(changes, " "))
+		}
+	}
+	if len(diff.Relinks) > 0 {
+		printf("Relinked chains (%d):\n", len(diff.Relinks))
+		for _, r := range diff.Relinks {
+			printf("  %-32s %-4s %s -> %s\n", r.Key, r.Pointer, orNone(r.Old), orNone(r.New))
+		}
+	}
+	return err
+}
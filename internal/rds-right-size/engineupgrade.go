@@ -0,0 +1,113 @@
+package rds_right_size
+
+import (
+	"sort"
+
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+)
+
+// EngineUpgrade groups the blocked generation upgrades that the same engine
+// version upgrade would unlock.
+type EngineUpgrade struct {
+	Engine                string
+	EngineVersion         string
+	RequiredEngineVersion string
+	Instances             int
+	MonthlySavings        float64
+}
+
+// blockedGenerationUpgrade finds the newer-generation class of the recommended
+// instance type that saves the most but is not orderable with the instance's
+// engine version. Like upgradeGeneration, it keeps the family prefix, suffix and
+// size, requires availability in the region and, for upscales, no less capacity.
+// Returns nil when there is none or it would not save money.
+func (r *RDSRightSize) blockedGenerationUpgrade(rec *types.Recommendation) *types.BlockedGenerationUpgrade {
+	if rec.Recommendation.RemovesInstance() || rec.RecommendedInstanceType == nil ||
+		rec.TargetInstanceProperties == nil || rec.EngineVersion == nil || *rec.EngineVersion == "" {
+		return nil
+	}
+	engineVersion := *rec.EngineVersion
+
+	targetInfo, ok := parseInstanceFamily(*rec.RecommendedInstanceType)
+	if !ok {
+		return nil
+	}
+	targetPrice := r.price(*rec.TargetInstanceProperties, &rec.Instance)
+
+	var best *types.BlockedGenerationUpgrade
+	for key, props := range r.instanceTypes {
+		info, ok := parseInstanceFamily(key)
+		if !ok || info.prefix != targetInfo.prefix || info.suffix != targetInfo.suffix ||
+			info.size != targetInfo.size || info.gen <= targetInfo.gen {
+			continue
+		}
+		if !keyMatchesEngine(key, rec.Engine) {
+			continue
+		}
+		if r.region != "" && !props.AvailableInRegion(r.region) {
+			continue
+		}
+		if r.supportsEngineVersion(props, engineVersion) {
+			continue
+		}
+		required := props.RequiredEngineVersion(r.region, engineVersion)
+		if required == "" {
+			continue
+		}
+		if rec.Recommendation == types.UpScale &&
+			(props.Vcpu < rec.TargetInstanceProperties.Vcpu || props.Mem < rec.TargetInstanceProperties.Mem) {
+			continue
+		}
+
+		savings := (targetPrice - r.price(props, &rec.Instance)) * hours_month
+		if savings <= 0 || (best != nil && savings <= best.MonthlySavings) {
+			continue
+		}
+		best = &types.BlockedGenerationUpgrade{
+			InstanceType:          stripEnginePrefix(key),
+			EngineVersion:         engineVersion,
+			RequiredEngineVersion: required,
+			MonthlySavings:        savings,
+		}
+	}
+	return best
+}
+
+// EngineUpgrades aggregates the blocked generation upgrades of recommendations by
+// engine, current and required version, sorted by savings (largest first).
+func EngineUpgrades(recommendations []types.Recommendation) []EngineUpgrade {
+	type upgradeKey struct{ engine, from, to string }
+	byKey := make(map[upgradeKey]*EngineUpgrade)
+	var upgrades []*EngineUpgrade
+	for _, rec := range recommendations {
+		blocked := rec.BlockedGenerationUpgrade
+		if blocked == nil {
+			continue
+		}
+		engine := ""
+		if rec.Engine != nil {
+			engine = *rec.Engine
+		}
+		key := upgradeKey{engine, blocked.EngineVersion, blocked.RequiredEngineVersion}
+		upgrade, ok := byKey[key]
+		if !ok {
+			upgrade = &EngineUpgrade{Engine: engine, EngineVersion: blocked.EngineVersion, RequiredEngineVersion: blocked.RequiredEngineVersion}
+			byKey[key] = upgrade
+			upgrades = append(upgrades, upgrade)
+		}
+		upgrade.Instances++
+		upgrade.MonthlySavings += blocked.MonthlySavings
+	}
+
+	result := make([]EngineUpgrade, 0, len(upgrades))
+	for _, upgrade := range upgrades {
+		result = append(result, *upgrade)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].MonthlySavings != result[j].MonthlySavings {
+			return result[i].MonthlySavings > result[j].MonthlySavings
+		}
+		return result[i].EngineVersion < result[j].EngineVersion
+	})
+	return result
+}
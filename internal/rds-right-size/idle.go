@@ -0,0 +1,83 @@
+package rds_right_size
+
+import (
+	"math"
+
+	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+)
+
+// likelyIdle classifies an instance that has connections as likely idle when its
+// peak connections match a monitoring-only level and CPU, IOPS and every workload
+// metric the engine publishes stay within the thresholds. It returns the evidence,
+// or nil when the instance is not idle or the metrics needed are missing.
+func likelyIdle(metrics *cwTypes.Metrics, thresholds types.IdleThresholds) []types.IdleSignal {
+	value := func(name cwTypes.RdsMetricName) *float64 {
+		if metric, ok := metrics.InstanceMetrics[name]; ok {
+			return metric.Value
+		}
+		return nil
+	}
+
+	connections := value(cwTypes.DatabaseConnections)
+	if connections == nil {
+		return nil
+	}
+	level := -1
+	for _, l := range thresholds.MonitoringConnections {
+		if int(math.Round(*connections)) == l {
+			level = l
+			break
+		}
+	}
+	if level < 0 {
+		return nil
+	}
+	evidence := []types.IdleSignal{{Metric: cwTypes.DatabaseConnections.String(), Value: *connections, Threshold: float64(level)}}
+
+	within := func(metric string, v *float64, threshold float64) bool {
+		if *v > threshold {
+			return false
+		}
+		evidence = append(evidence, types.IdleSignal{Metric: metric, Value: *v, Threshold: threshold})
+		return true
+	}
+
+	cpu := value(cwTypes.CPUUtilization)
+	if cpu == nil || !within(cwTypes.CPUUtilization.String(), cpu, thresholds.CPU) {
+		return nil
+	}
+
+	readIOPS, writeIOPS := value(cwTypes.ReadIOPS), value(cwTypes.WriteIOPS)
+	if readIOPS != nil && writeIOPS != nil {
+		iops := *readIOPS + *writeIOPS
+		if !within("IOPS", &iops, thresholds.IOPS) {
+			return nil
+		}
+	}
+
+	// At least one workload metric is required to tell monitoring from real traffic
+	workload := 0
+	for _, w := range []struct {
+		name      cwTypes.RdsMetricName
+		threshold float64
+	}{
+		{cwTypes.Queries, thresholds.Queries},
+		{cwTypes.CommitThroughput, thresholds.CommitThroughput},
+		{cwTypes.DMLThroughput, thresholds.DMLThroughput},
+	} {
+		v := value(w.name)
+		if v == nil {
+			continue
+		}
+		if !within(w.name.String(), v, w.threshold) {
+			return nil
+		}
+		workload++
+	}
+	if workload == 0 {
+		return nil
+	}
+
+	return evidence
+}
@@ -6,12 +6,22 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/luneo7/rds-right-size/internal/cache"
+	"github.com/luneo7/rds-right-size/internal/cur"
+	"github.com/luneo7/rds-right-size/internal/currency"
 	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
+	"github.com/luneo7/rds-right-size/internal/org"
 	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
 	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
 )
 
+// DefaultMaxConcurrency is the default number of account/region analyses run at once.
+const DefaultMaxConcurrency = 8
+
 // MultiRegionOptions configures a parallel multi-region analysis.
 type MultiRegionOptions struct {
 	Regions          []string
@@ -24,63 +34,163 @@ type MultiRegionOptions struct {
 	MemUpsize        float64
 	Stat             cwTypes.StatName
 	PreferNewGen     bool
+	PricingModel     types.PricingModel
+	PricingOverrides *types.PricingOverrides
 	FetchTimeSeries  bool
+	ReservedCoverage bool
+	StorageAnalysis  bool
+	IncludeOptimized bool
+	Explain          bool
+	MinConfidence    float64
+	MinWindowDays    int
+	IdleThresholds   *types.IdleThresholds
+	CURCosts         cur.Costs
+	Currency         *currency.Currency
+	Cache            *cache.Cache
+
+	// Accounts lists account IDs to analyze by assuming RoleName in each.
+	// When both Accounts and OrganizationalUnit are empty, only the profile's
+	// own account is analyzed.
+	Accounts []string
+
+	// OrganizationalUnit is an AWS Organizations OU (or root) ID whose active
+	// accounts, including those in nested OUs, are added to Accounts. Listing
+	// requires organizations:ListAccountsForParent and
+	// organizations:ListOrganizationalUnitsForParent with the profile's credentials.
+	OrganizationalUnit string
+
+	// RoleName is the IAM role assumed in each account (via STS AssumeRole).
+	// Required when Accounts or OrganizationalUnit is set.
+	RoleName string
+
+	// MaxConcurrency caps how many account/region analyses run at once.
+	// Defaults to DefaultMaxConcurrency.
+	MaxConcurrency int
 
 	// OnProgress is called with aggregated progress across all regions.
-	// instanceLabel already includes the region suffix, e.g. "my-db (us-east-1)".
+	// instanceLabel already includes the region suffix, e.g. "my-db (us-east-1)",
+	// or the account and region in multi-account mode, e.g. "my-db (123456789012/us-east-1)".
 	OnProgress func(current, total int, instanceLabel string)
 
 	// OnWarning is called when an instance is skipped. instanceLabel includes region.
 	OnWarning func(instanceLabel, msg string)
 
-	// OnRegionError is called when a region's analysis fails.
+	// OnInstanceTypesWarning is called for validation errors in the instance types
+	// that do not prevent analysis.
+	OnInstanceTypesWarning func(msg string)
+
+	// OnRegionError is called when a region's analysis fails. In multi-account mode
+	// region is "<account>/<region>".
 	// It is informational; analysis continues for other regions.
 	// If all regions fail, AnalyzeMultiRegion returns an error.
 	OnRegionError func(region string, err error)
 }
 
-// AnalyzeMultiRegion runs AnalyzeRDS in parallel across all regions in opts.Regions.
-// Each region gets its own AWS config derived from opts.Profile.
-// Results are merged, stamped with their region, sorted, and returned.
+// analysisTarget is one account/region pair of a multi-region analysis.
+type analysisTarget struct {
+	account string // empty for the profile's own account
+	region  string
+}
+
+func (t analysisTarget) label() string {
+	if t.account == "" {
+		return t.region
+	}
+	return t.account + "/" + t.region
+}
+
+// AnalyzeMultiRegion runs AnalyzeRDS in parallel across all regions in opts.Regions,
+// and across all accounts when opts.Accounts or opts.OrganizationalUnit is set.
+// Each region gets its own AWS config derived from opts.Profile, with credentials
+// from assuming opts.RoleName in multi-account mode.
+// Results are merged, stamped with their region (and account), sorted, and returned.
 // Returns an error only if every region fails; partial success is surfaced via OnRegionError.
 func AnalyzeMultiRegion(ctx context.Context, opts MultiRegionOptions) ([]types.Recommendation, []string, error) {
 	type regionResult struct {
-		region          string
+		target          analysisTarget
 		recommendations []types.Recommendation
 		warnings        []string
 		err             error
 	}
 
+	if len(opts.Regions) == 0 {
+		return nil, nil, fmt.Errorf("at least one region is required")
+	}
+
+	var profileOptFns []func(*config.LoadOptions) error
+	if opts.Profile != "" {
+		profileOptFns = append(profileOptFns, config.WithSharedConfigProfile(opts.Profile))
+	}
+
+	// Loaded once and shared read-only by every account/region analyzer
+	instanceTypes, err := LoadInstanceTypes(opts.InstanceTypesURL, opts.PricingOverrides, opts.Cache)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.OnInstanceTypesWarning != nil {
+		for _, warning := range instanceTypes.Warnings {
+			opts.OnInstanceTypesWarning(warning)
+		}
+	}
+
+	accounts, err := resolveAccounts(ctx, opts, profileOptFns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var targets []analysisTarget
+	if len(accounts) == 0 {
+		for _, rgn := range opts.Regions {
+			targets = append(targets, analysisTarget{region: rgn})
+		}
+	} else {
+		for _, account := range accounts {
+			for _, rgn := range opts.Regions {
+				targets = append(targets, analysisTarget{account: account, region: rgn})
+			}
+		}
+	}
+
 	var mu sync.Mutex
 	type progress struct{ current, total int }
-	regionProg := make(map[string]*progress)
-	for _, rgn := range opts.Regions {
-		regionProg[rgn] = &progress{}
+	targetProg := make(map[analysisTarget]*progress)
+	for _, target := range targets {
+		targetProg[target] = &progress{}
 	}
 
-	results := make([]regionResult, len(opts.Regions))
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+	sem := make(chan struct{}, maxConcurrency)
+
+	results := make([]regionResult, len(targets))
 	var wg sync.WaitGroup
 
-	for i, rgn := range opts.Regions {
+	for i, target := range targets {
 		wg.Add(1)
-		go func(idx int, rgn string) {
+		go func(idx int, target analysisTarget) {
 			defer wg.Done()
 
-			var optFns []func(*config.LoadOptions) error
-			if opts.Profile != "" {
-				optFns = append(optFns, config.WithSharedConfigProfile(opts.Profile))
-			}
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			rgn := target.region
+			optFns := append([]func(*config.LoadOptions) error{}, profileOptFns...)
 			optFns = append(optFns, config.WithRegion(rgn))
 
 			cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 			if err != nil {
-				results[idx] = regionResult{region: rgn, err: err}
+				results[idx] = regionResult{target: target, err: err}
 				return
 			}
+			if target.account != "" {
+				cfg.Credentials = assumeRoleCredentials(cfg, target.account, opts.RoleName)
+			}
 
-			instanceTypesURL := opts.InstanceTypesURL
 			analyzer := NewRDSRightSize(
-				&instanceTypesURL,
+				instanceTypes,
+				opts.InstanceTypesURL,
 				&cfg,
 				opts.Period,
 				opts.Tags,
@@ -90,29 +200,41 @@ func AnalyzeMultiRegion(ctx context.Context, opts MultiRegionOptions) ([]types.R
 				opts.Stat,
 				opts.PreferNewGen,
 				rgn,
+				opts.PricingModel,
+				opts.PricingOverrides,
 			)
 
 			var regionWarnings []string
 			analysisOpts := &AnalysisOptions{
-				FetchTimeSeries: opts.FetchTimeSeries,
+				FetchTimeSeries:  opts.FetchTimeSeries,
+				ReservedCoverage: opts.ReservedCoverage,
+				StorageAnalysis:  opts.StorageAnalysis,
+				IncludeOptimized: opts.IncludeOptimized,
+				Explain:          opts.Explain,
+				MinConfidence:    opts.MinConfidence,
+				MinWindowDays:    opts.MinWindowDays,
+				IdleThresholds:   opts.IdleThresholds,
+				CURCosts:         opts.CURCosts,
+				Currency:         opts.Currency,
+				Cache:            opts.Cache,
 				OnProgress: func(current, total int, instanceId string) {
 					if opts.OnProgress == nil {
 						return
 					}
 					mu.Lock()
-					rp := regionProg[rgn]
-					rp.current = current
-					rp.total = total
+					tp := targetProg[target]
+					tp.current = current
+					tp.total = total
 					var totalSum, currentSum int
-					for _, p := range regionProg {
+					for _, p := range targetProg {
 						totalSum += p.total
 						currentSum += p.current
 					}
 					mu.Unlock()
-					opts.OnProgress(currentSum, totalSum, fmt.Sprintf("%s (%s)", instanceId, rgn))
+					opts.OnProgress(currentSum, totalSum, fmt.Sprintf("%s (%s)", instanceId, target.label()))
 				},
 				OnWarning: func(instanceId, msg string) {
-					label := fmt.Sprintf("%s (%s)", instanceId, rgn)
+					label := fmt.Sprintf("%s (%s)", instanceId, target.label())
 					if opts.OnWarning != nil {
 						opts.OnWarning(label, msg)
 					}
@@ -124,20 +246,21 @@ func AnalyzeMultiRegion(ctx context.Context, opts MultiRegionOptions) ([]types.R
 
 			recommendations, err := analyzer.AnalyzeRDS(ctx, analysisOpts)
 			if err != nil {
-				results[idx] = regionResult{region: rgn, err: err}
+				results[idx] = regionResult{target: target, err: err}
 				return
 			}
 
-			// Stamp region on each recommendation
+			// Stamp region and account on each recommendation
 			for j := range recommendations {
 				recommendations[j].Region = rgn
+				recommendations[j].AccountId = target.account
 			}
 			results[idx] = regionResult{
-				region:          rgn,
+				target:          target,
 				recommendations: recommendations,
 				warnings:        regionWarnings,
 			}
-		}(i, rgn)
+		}(i, target)
 	}
 
 	wg.Wait()
@@ -149,9 +272,9 @@ func AnalyzeMultiRegion(ctx context.Context, opts MultiRegionOptions) ([]types.R
 
 	for _, r := range results {
 		if r.err != nil {
-			errs = append(errs, fmt.Sprintf("%s: %v", r.region, r.err))
+			errs = append(errs, fmt.Sprintf("%s: %v", r.target.label(), r.err))
 			if opts.OnRegionError != nil {
-				opts.OnRegionError(r.region, r.err)
+				opts.OnRegionError(r.target.label(), r.err)
 			}
 			continue
 		}
@@ -167,3 +290,73 @@ func AnalyzeMultiRegion(ctx context.Context, opts MultiRegionOptions) ([]types.R
 
 	return allRecs, allWarnings, nil
 }
+
+// resolveAccounts merges opts.Accounts with the active accounts of
+// opts.OrganizationalUnit, without duplicates and in a stable order.
+func resolveAccounts(ctx context.Context, opts MultiRegionOptions, profileOptFns []func(*config.LoadOptions) error) ([]string, error) {
+	if len(opts.Accounts) == 0 && opts.OrganizationalUnit == "" {
+		return nil, nil
+	}
+	if opts.RoleName == "" {
+		return nil, fmt.Errorf("a role name is required to analyze other accounts")
+	}
+
+	accounts := append([]string{}, opts.Accounts...)
+	if opts.OrganizationalUnit != "" {
+		optFns := append(append([]func(*config.LoadOptions) error{}, profileOptFns...), config.WithRegion(opts.Regions[0]))
+		cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+		if err != nil {
+			return nil, err
+		}
+		ouAccounts, err := org.NewOrganizations(&cfg).GetActiveAccounts(ctx, opts.OrganizationalUnit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts of %s: %w", opts.OrganizationalUnit, err)
+		}
+		if len(ouAccounts) == 0 {
+			return nil, fmt.Errorf("no active accounts found under %s", opts.OrganizationalUnit)
+		}
+		accounts = append(accounts, ouAccounts...)
+	}
+
+	seen := make(map[string]bool, len(accounts))
+	unique := accounts[:0]
+	for _, account := range accounts {
+		if !seen[account] {
+			seen[account] = true
+			unique = append(unique, account)
+		}
+	}
+	return unique, nil
+}
+
+// assumeRoleCredentials returns cached credentials for roleName in account, assumed
+// with the credentials of cfg.
+func assumeRoleCredentials(cfg aws.Config, account, roleName string) aws.CredentialsProvider {
+	roleArn := fmt.Sprintf("arn:%s:iam::%s:role/%s", partition(cfg.Region), account, strings.TrimPrefix(roleName, "/"))
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = "rds-right-size"
+	})
+	return aws.NewCredentialsCache(provider)
+}
+
+// partitionPrefixes maps region name prefixes to their AWS partition; regions
+// matching none are in the standard "aws" partition.
+var partitionPrefixes = []struct{ prefix, partition string }{
+	{"cn-", "aws-cn"},
+	{"us-gov-", "aws-us-gov"},
+	{"us-isob-", "aws-iso-b"},
+	{"us-isof-", "aws-iso-f"},
+	{"us-iso-", "aws-iso"},
+	{"eu-isoe-", "aws-iso-e"},
+}
+
+// partition returns the AWS partition of a region, used in the ARNs of roles
+// assumed there.
+func partition(region string) string {
+	for _, p := range partitionPrefixes {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.partition
+		}
+	}
+	return "aws"
+}
@@ -0,0 +1,204 @@
+package rds_right_size
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+)
+
+// LoadPricingOverrides reads and validates a pricing overrides JSON file.
+func LoadPricingOverrides(path string) (*types.PricingOverrides, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing overrides file %s: %w", path, err)
+	}
+
+	var overrides types.PricingOverrides
+	if err := json.Unmarshal(body, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing overrides JSON from %s: %w", path, err)
+	}
+
+	if overrides.Currency == "" {
+		overrides.Currency = "USD"
+	}
+	overrides.Currency = strings.ToUpper(overrides.Currency)
+	if overrides.Currency == "USD" && overrides.ExchangeRate == 0 {
+		overrides.ExchangeRate = 1
+	}
+	if overrides.ExchangeRate <= 0 {
+		return nil, fmt.Errorf("pricing overrides in %s must set a positive exchangeRate (%s per USD)", overrides.Currency, overrides.Currency)
+	}
+	if overrides.DiscountPercent < 0 || overrides.DiscountPercent >= 100 {
+		return nil, fmt.Errorf("pricing overrides discountPercent must be between 0 and 100, got %.2f", overrides.DiscountPercent)
+	}
+	for key, m := range overrides.FamilyMultipliers {
+		if m <= 0 {
+			return nil, fmt.Errorf("pricing overrides family multiplier for %s must be positive", key)
+		}
+	}
+	for key, m := range overrides.ClassMultipliers {
+		if m <= 0 {
+			return nil, fmt.Errorf("pricing overrides class multiplier for %s must be positive", key)
+		}
+	}
+	for region, prices := range overrides.Prices {
+		for key, price := range prices {
+			if price <= 0 {
+				return nil, fmt.Errorf("pricing overrides price for %s in %s must be positive", key, region)
+			}
+		}
+	}
+
+	return &overrides, nil
+}
+
+// applyPricingOverrides returns a copy of instanceTypes with the overrides applied.
+// Override keys may be plain ("db.r6g.large", "db.r6g") or engine-prefixed
+// ("aurora-postgresql:db.r6g.large"); the prefixed form takes precedence. Keys that
+// match no instance class, and absolute prices for regions where a class has no
+// public price, are reported as errors so typos don't go unnoticed.
+func applyPricingOverrides(instanceTypes types.InstanceTypes, overrides *types.PricingOverrides) (types.InstanceTypes, error) {
+	if overrides == nil {
+		return instanceTypes, nil
+	}
+
+	known := make(map[string]bool)
+	for key := range instanceTypes {
+		class := stripEnginePrefix(key)
+		known[key] = true
+		known[class] = true
+		known[prefixedFamilyName(key)] = true
+		known[instanceFamilyName(class)] = true
+	}
+	var unknown []string
+	for key := range overrides.FamilyMultipliers {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	for key := range overrides.ClassMultipliers {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	for _, prices := range overrides.Prices {
+		for key := range prices {
+			if !known[key] {
+				unknown = append(unknown, key)
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("pricing overrides reference unknown instance classes or families: %s", strings.Join(unknown, ", "))
+	}
+
+	discount := 1 - overrides.DiscountPercent/100
+	result := make(types.InstanceTypes, len(instanceTypes))
+	for key, props := range instanceTypes {
+		multiplier := overrideMultiplier(key, overrides)
+
+		// Per-region factor: absolute prices replace the public on-demand rate
+		factors := make(map[string]float64)
+		for region, prices := range overrides.Prices {
+			price, ok := prices[key]
+			if !ok {
+				price, ok = prices[stripEnginePrefix(key)]
+			}
+			if !ok {
+				continue
+			}
+			public, ok := props.Pricing[region]
+			if !ok || public <= 0 {
+				return nil, fmt.Errorf("pricing overrides set a price for %s in %s, which has no public price there", key, region)
+			}
+			factors[region] = price / overrides.ExchangeRate / public
+		}
+		factor := func(region string) float64 {
+			if f, ok := factors[region]; ok {
+				return f * discount
+			}
+			return multiplier * discount
+		}
+
+		props.StdPrice *= multiplier * discount
+		if props.Pricing != nil {
+			pricing := maps.Clone(props.Pricing)
+			for region := range pricing {
+				pricing[region] *= factor(region)
+			}
+			props.Pricing = pricing
+		}
+		if props.IOOptimizedPricing != nil {
+			ioPricing := maps.Clone(props.IOOptimizedPricing)
+			for region := range ioPricing {
+				ioPricing[region] *= factor(region)
+			}
+			props.IOOptimizedPricing = ioPricing
+		}
+		if props.ReservedPricing != nil {
+			reserved := make(map[string]map[types.PricingModel]float64, len(props.ReservedPricing))
+			for region, rates := range props.ReservedPricing {
+				scaled := maps.Clone(rates)
+				for model := range scaled {
+					scaled[model] *= factor(region)
+				}
+				reserved[region] = scaled
+			}
+			props.ReservedPricing = reserved
+		}
+
+		result[key] = props
+	}
+
+	return result, nil
+}
+
+// applyStorageOverrides applies the global discount to Aurora storage and I/O rates.
+func applyStorageOverrides(pricing types.StoragePricing, overrides *types.PricingOverrides) types.StoragePricing {
+	if overrides == nil || overrides.DiscountPercent == 0 {
+		return pricing
+	}
+	discount := 1 - overrides.DiscountPercent/100
+	result := make(types.StoragePricing, len(pricing))
+	for region, prices := range pricing {
+		result[region] = types.StoragePrices{
+			StandardStorage:    prices.StandardStorage * discount,
+			IOOptimizedStorage: prices.IOOptimizedStorage * discount,
+			StandardIO:         prices.StandardIO * discount,
+		}
+	}
+	return result
+}
+
+// overrideMultiplier returns the class or family multiplier for an instance types key,
+// preferring engine-prefixed keys and class over family. Defaults to 1.
+func overrideMultiplier(key string, overrides *types.PricingOverrides) float64 {
+	class := stripEnginePrefix(key)
+	for _, candidate := range []string{key, class} {
+		if m, ok := overrides.ClassMultipliers[candidate]; ok {
+			return m
+		}
+	}
+	for _, candidate := range []string{prefixedFamilyName(key), instanceFamilyName(class)} {
+		if m, ok := overrides.FamilyMultipliers[candidate]; ok {
+			return m
+		}
+	}
+	return 1
+}
+
+// prefixedFamilyName returns the family of an instance types key, keeping its
+// engine prefix ("aurora-postgresql:db.r6g.large" -> "aurora-postgresql:db.r6g").
+func prefixedFamilyName(key string) string {
+	family := instanceFamilyName(key)
+	if idx := strings.Index(key, ":"); idx >= 0 && family != "" {
+		return key[:idx+1] + family
+	}
+	return family
+}
@@ -0,0 +1,151 @@
+package rds_right_size
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
+)
+
+// currentGenerationEquivalent finds the closest current-generation class to a
+// previous-generation one: the same family prefix (r, m, t, ...) with at least
+// its vCPU and memory, orderable for the instance. Among those it picks the
+// smallest, then one keeping the architecture suffix, then the cheapest.
+// Returns the plain class name and its properties.
+func (r *RDSRightSize) currentGenerationEquivalent(cls string, props types.InstanceProperties, instance *rdsTypes.Instance) (string, types.InstanceProperties, bool) {
+	info, ok := parseInstanceFamily(cls)
+	if !ok {
+		return "", types.InstanceProperties{}, false
+	}
+	engineVersion := aws.ToString(instance.EngineVersion)
+
+	var bestKey string
+	var bestProps types.InstanceProperties
+	better := func(key string, candidate types.InstanceProperties, candidateInfo instanceFamilyInfo) bool {
+		if bestKey == "" {
+			return true
+		}
+		if candidate.Vcpu != bestProps.Vcpu {
+			return candidate.Vcpu < bestProps.Vcpu
+		}
+		if candidate.Mem != bestProps.Mem {
+			return candidate.Mem < bestProps.Mem
+		}
+		bestInfo, _ := parseInstanceFamily(bestKey)
+		if sameSuffix, bestSameSuffix := candidateInfo.suffix == info.suffix, bestInfo.suffix == info.suffix; sameSuffix != bestSameSuffix {
+			return sameSuffix
+		}
+		if price, bestPrice := r.price(candidate, instance), r.price(bestProps, instance); price != bestPrice {
+			return price < bestPrice
+		}
+		return key < bestKey
+	}
+
+	for key, candidate := range r.instanceTypes {
+		if candidate.PreviousGeneration || !keyMatchesEngine(key, instance.Engine) {
+			continue
+		}
+		candidateInfo, ok := parseInstanceFamily(key)
+		if !ok || candidateInfo.prefix != info.prefix {
+			continue
+		}
+		if candidate.Vcpu < props.Vcpu || candidate.Mem < props.Mem {
+			continue
+		}
+		if !r.orderable(candidate, engineVersion) {
+			continue
+		}
+		if better(key, candidate, candidateInfo) {
+			bestKey = key
+			bestProps = candidate
+		}
+	}
+
+	if bestKey == "" {
+		return "", types.InstanceProperties{}, false
+	}
+	return stripEnginePrefix(bestKey), bestProps, true
+}
+
+// migratePreviousGeneration moves an analyzed instance off previous-generation
+// classes. A scaling recommendation (the last of recommendations when hasRec)
+// targeting one is retargeted to its current-generation equivalent when the
+// equivalent passes the same checks as generation upgrades (throughput and projected
+// CPU for downscales, capacity for upscales), and an instance left without a
+// recommendation on one gets a PreviousGenerationReason recommendation to its
+// equivalent, even though its utilization is optimized. Both are recorded in the
+// decision trace.
+func (r *RDSRightSize) migratePreviousGeneration(
+	recommendations []types.Recommendation,
+	hasRec bool,
+	instance rdsTypes.Instance,
+	props *types.InstanceProperties,
+	metrics *cwTypes.Metrics,
+	cpuValue *float64,
+	tsMetrics *cwTypes.TimeSeriesMetrics,
+) []types.Recommendation {
+	engineVersion := aws.ToString(instance.EngineVersion)
+
+	if hasRec {
+		rec := &recommendations[len(recommendations)-1]
+		if rec.Recommendation.RemovesInstance() || rec.TargetInstanceProperties == nil ||
+			rec.RecommendedInstanceType == nil || !rec.TargetInstanceProperties.PreviousGeneration {
+			return recommendations
+		}
+		name, target, found := r.currentGenerationEquivalent(*rec.RecommendedInstanceType, *rec.TargetInstanceProperties, &instance)
+		if !found {
+			return recommendations
+		}
+
+		trace := r.newTrace()
+		defer func() {
+			rec.DecisionTrace = append(rec.DecisionTrace, trace.Steps()...)
+		}()
+		step := trace.candidate(types.PreviousGenerationStage, name)
+		if !r.checkOrderable(step, target, engineVersion) {
+			step.reject("not orderable")
+			return recommendations
+		}
+		var bandwidthTotal *float64
+		if bandwidth, err := r.getBandwidthUtilization(metrics, props); err == nil {
+			bandwidthTotal = bandwidth.Total
+		}
+		if !r.checkReplacementTarget(step, rec, props, target, cpuValue, bandwidthTotal) {
+			return recommendations
+		}
+		step.accept("current-generation equivalent of " + *rec.RecommendedInstanceType)
+
+		rec.RecommendedInstanceType = &name
+		rec.TargetInstanceProperties = &target
+		rec.MonthlyApproximatePriceDiff = Float64((r.price(target, &instance) - r.price(*props, &instance)) * hours_month)
+		return recommendations
+	}
+
+	if !props.PreviousGeneration {
+		return recommendations
+	}
+	name, target, found := r.currentGenerationEquivalent(*instance.DBInstanceClass, *props, &instance)
+	if !found {
+		return recommendations
+	}
+
+	trace := r.newTrace()
+	step := trace.candidate(types.PreviousGenerationStage, name)
+	r.checkOrderable(step, target, engineVersion)
+	step.check(checkCapacity, true, capacityValue(target, *props))
+	step.accept("current-generation equivalent of " + *instance.DBInstanceClass)
+
+	// The equivalent never has less capacity; like generation upgrades, it is an upscale
+	return append(recommendations, types.Recommendation{
+		Instance:                    instance,
+		Recommendation:              types.UpScale,
+		Reason:                      types.PreviousGenerationReason,
+		RecommendedInstanceType:     &name,
+		MetricValue:                 cpuValue,
+		MonthlyApproximatePriceDiff: Float64((r.price(target, &instance) - r.price(*props, &instance)) * hours_month),
+		CurrentInstanceProperties:   props,
+		TargetInstanceProperties:    &target,
+		TimeSeriesMetrics:           tsMetrics,
+		DecisionTrace:               trace.Steps(),
+	})
+}
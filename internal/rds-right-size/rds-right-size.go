@@ -6,8 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
-	"net/http"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -17,12 +16,14 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/smithy-go/ptr"
+	"github.com/luneo7/rds-right-size/internal/cache"
+	"github.com/luneo7/rds-right-size/internal/cur"
+	"github.com/luneo7/rds-right-size/internal/currency"
 	"github.com/luneo7/rds-right-size/internal/cw"
 	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
 	"github.com/luneo7/rds-right-size/internal/rds"
 	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
 	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
-	"github.com/luneo7/rds-right-size/internal/util"
 )
 
 const (
@@ -47,6 +48,56 @@ type AnalysisOptions struct {
 	// OnWarning is an optional callback invoked when an instance is skipped
 	// due to missing CloudWatch metrics (e.g., transient auto-scaling replicas).
 	OnWarning func(instanceId string, msg string)
+
+	// ReservedCoverage reads the region's active reserved DB instances and computes
+	// effective savings (EffectiveMonthlyPriceDiff) that account for size-flexible
+	// reservation coverage. Defaults to false.
+	ReservedCoverage bool
+
+	// StorageAnalysis models each Aurora cluster's monthly cost under Standard and
+	// I/O-Optimized storage and emits StorageConfiguration recommendations when the
+	// other configuration is cheaper. Requires the storage pricing file written by
+	// generate-types next to the instance types file. Defaults to false.
+	StorageAnalysis bool
+
+	// IncludeOptimized also returns an Optimized recommendation, with its measured
+	// Metrics, for each analyzed instance that needs no change, and a Skipped
+	// recommendation, with its SkipReason, for each instance that could not be
+	// analyzed. Defaults to false.
+	IncludeOptimized bool
+
+	// Explain records on each recommendation the candidate classes considered by
+	// the scaling walks, generation upgrades and cluster equalization, with the
+	// checks that accepted or rejected them (DecisionTrace). Defaults to false.
+	Explain bool
+
+	// MinConfidence drops instance recommendations whose Confidence score (0-100)
+	// is below it. Skipped and Unavailable instances and cluster storage
+	// recommendations are always returned. Zero keeps every recommendation.
+	MinConfidence float64
+
+	// MinWindowDays flags recommendations (ShortMetricsWindow) whose metrics were
+	// restricted to less than this many days after an instance class change.
+	// Defaults to DefaultMinWindowDays.
+	MinWindowDays int
+
+	// IdleThresholds configures the classifier that recommends LikelyIdle for
+	// instances whose only connections and activity come from monitoring. Nil uses
+	// types.DefaultIdleThresholds.
+	IdleThresholds *types.IdleThresholds
+
+	// CURCosts holds per-instance costs parsed from a Cost and Usage Report export
+	// (see cur.Load). When set, cost differences of matching instances are scaled by
+	// their effective discount ratio.
+	CURCosts cur.Costs
+
+	// Currency is the reporting currency. Cost amounts are converted from USD at its
+	// rate after all other adjustments. Nil reports in USD.
+	Currency *currency.Currency
+
+	// Cache is the on-disk download cache for the storage pricing file. Nil
+	// downloads without caching.
+	Cache *cache.Cache
 }
 
 type RDSRightSize struct {
@@ -62,8 +113,15 @@ type RDSRightSize struct {
 	statistic            cwTypes.StatName
 	preferNewGen         bool
 	region               string
+	pricingModel         types.PricingModel
+	storagePricingSource string
+	storagePricing       types.StoragePricing
+	pricingOverrides     *types.PricingOverrides
+	provenance           *types.InstanceTypesProvenance
 	// maxConnCache caches GetMaxConnections results per parameter group name
 	maxConnCache map[string]*int64
+	// explain records decision traces on recommendations (AnalysisOptions.Explain)
+	explain bool
 }
 
 // clusterInstanceInfo tracks per-instance analysis data for cluster equalization.
@@ -76,13 +134,29 @@ type clusterInstanceInfo struct {
 	recIndex   int // index in recommendations slice, or -1 if no recommendation
 }
 
-func NewRDSRightSize(instanceTypesUrl *string, awsConfig *aws.Config, period int, tags rdsTypes.Tags, cpuDownsizeThreshold float64, cpuUpsizeThreshold float64, memUpsizeThreshold float64, statistic cwTypes.StatName, preferNewGen bool, region string) *RDSRightSize {
+// NewRDSRightSize creates an analyzer for one region. instanceTypes must come from
+// LoadInstanceTypes and is shared read-only, so a single load can back analyzers for
+// every region and account of a run; instanceTypesSource is where it was loaded from,
+// recorded as provenance and used to locate the storage pricing file of legacy files.
+func NewRDSRightSize(instanceTypes *types.InstanceTypesFile, instanceTypesSource string, awsConfig *aws.Config, period int, tags rdsTypes.Tags, cpuDownsizeThreshold float64, cpuUpsizeThreshold float64, memUpsizeThreshold float64, statistic cwTypes.StatName, preferNewGen bool, region string, pricingModel types.PricingModel, pricingOverrides *types.PricingOverrides) *RDSRightSize {
+	if pricingModel == "" {
+		pricingModel = types.OnDemand
+	}
+
+	// Versioned files embed the storage pricing; legacy ones have a sidecar file
+	storagePricingSource := types.StoragePricingPath(instanceTypesSource)
+	var storagePricing types.StoragePricing
+	if len(instanceTypes.StoragePricing) > 0 {
+		storagePricingSource = instanceTypesSource
+		storagePricing = instanceTypes.StoragePricing
+	}
+
 	return &RDSRightSize{
 		rds:                  rds.NewRDS(awsConfig),
 		cloudWatch:           cw.NewCloudWatch(awsConfig),
 		period:               period,
 		tags:                 tags,
-		instanceTypes:        loadInstanceTypes(instanceTypesUrl),
+		instanceTypes:        instanceTypes.InstanceTypes,
 		armInstanceRegex:     regexp.MustCompile(`db\..*g\..*`),
 		cpuDownsizeThreshold: cpuDownsizeThreshold,
 		cpuUpsizeThreshold:   cpuUpsizeThreshold,
@@ -90,7 +164,16 @@ func NewRDSRightSize(instanceTypesUrl *string, awsConfig *aws.Config, period int
 		statistic:            statistic,
 		preferNewGen:         preferNewGen,
 		region:               region,
-		maxConnCache:         make(map[string]*int64),
+		pricingModel:         pricingModel,
+		storagePricingSource: storagePricingSource,
+		storagePricing:       storagePricing,
+		provenance: &types.InstanceTypesProvenance{
+			Source:        instanceTypesSource,
+			SchemaVersion: instanceTypes.SchemaVersion,
+			Metadata:      instanceTypes.Metadata,
+		},
+		pricingOverrides: pricingOverrides,
+		maxConnCache:     make(map[string]*int64),
 	}
 }
 
@@ -99,6 +182,40 @@ func (r *RDSRightSize) GetInstanceTypes() types.InstanceTypes {
 	return r.instanceTypes
 }
 
+// price returns the hourly price of an instance class in the analyzer's region
+// under the configured pricing model and the instance's cluster storage type.
+func (r *RDSRightSize) price(props types.InstanceProperties, instance *rdsTypes.Instance) float64 {
+	storageType := ""
+	if instance != nil && instance.StorageType != nil {
+		storageType = *instance.StorageType
+	}
+	return props.GetInstancePrice(r.region, r.pricingModel, storageType)
+}
+
+// onDemandPriceFallback reports whether a recommendation's current or target class
+// has no reserved rate for the configured pricing model in the region, so its costs
+// were computed from the on-demand price.
+func (r *RDSRightSize) onDemandPriceFallback(rec *types.Recommendation) bool {
+	for _, props := range []*types.InstanceProperties{rec.CurrentInstanceProperties, rec.TargetInstanceProperties} {
+		if props != nil && !props.HasReservedPrice(r.region, r.pricingModel) {
+			return true
+		}
+	}
+	return false
+}
+
+// OnDemandPriceFallbackCount returns how many recommendations were priced on demand
+// for lack of a reserved rate under the selected pricing model.
+func OnDemandPriceFallbackCount(recommendations []types.Recommendation) int {
+	count := 0
+	for _, rec := range recommendations {
+		if rec.OnDemandPriceFallback {
+			count++
+		}
+	}
+	return count
+}
+
 // lookupInstanceProperties resolves an instance class name to its properties,
 // supporting both engine-prefixed keys (from "both" engine generation) and
 // plain keys (from single-engine generation).
@@ -170,9 +287,9 @@ func parseInstanceFamily(dbInstanceClass string) (instanceFamilyInfo, bool) {
 // upgradeGeneration attempts to find a newer-generation instance with the same
 // family prefix, architecture suffix, and size in the loaded instance types.
 // Strict suffix matching ensures no architecture change (e.g., r6g → r7g only, never r6g → r7).
-// It also verifies regional availability and engine version compatibility.
+// It also verifies the candidate is orderable in the region with the engine version.
 // Returns the map key, properties, and true if a newer generation was found.
-func (r *RDSRightSize) upgradeGeneration(targetClass string, engine *string, engineVersion string) (string, types.InstanceProperties, bool) {
+func (r *RDSRightSize) upgradeGeneration(targetClass string, engine *string, engineVersion string, trace *decisionTrace) (string, types.InstanceProperties, bool) {
 	targetInfo, ok := parseInstanceFamily(targetClass)
 	if !ok {
 		return "", types.InstanceProperties{}, false
@@ -182,7 +299,14 @@ func (r *RDSRightSize) upgradeGeneration(targetClass string, engine *string, eng
 	var bestProps types.InstanceProperties
 	bestGen := targetInfo.gen
 
+	// Sorted so that traces (and ties between equal generations) are stable
+	keys := make([]string, 0, len(r.instanceTypes))
 	for key := range r.instanceTypes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
 		bare := stripEnginePrefix(key)
 		info, ok := parseInstanceFamily(bare)
 		if !ok {
@@ -195,32 +319,29 @@ func (r *RDSRightSize) upgradeGeneration(targetClass string, engine *string, eng
 		}
 
 		// Must be a newer generation
-		if info.gen <= bestGen {
+		if info.gen <= targetInfo.gen {
 			continue
 		}
 
-		// If using engine-prefixed keys, ensure engine matches
-		if engine != nil && *engine != "" && strings.Contains(key, ":") {
-			keyEngine := key[:strings.Index(key, ":")]
-			if keyEngine != *engine {
-				continue
-			}
+		if !keyMatchesEngine(key, engine) {
+			continue
 		}
 
 		candidateProps := r.instanceTypes[key]
+		step := trace.candidate(types.GenerationUpgradeStage, key)
 
-		// Must be available in the user's region
-		if r.region != "" && !candidateProps.AvailableInRegion(r.region) {
+		// Must be orderable in the user's region with the user's engine version
+		if !r.checkOrderable(step, candidateProps, engineVersion) {
+			step.reject("not orderable")
 			continue
 		}
 
-		// Must be compatible with the user's engine version
-		if candidateProps.MinEngineVersion != "" && engineVersion != "" {
-			if util.CompareVersions(engineVersion, candidateProps.MinEngineVersion) < 0 {
-				continue
-			}
+		if info.gen <= bestGen {
+			step.reject("not newer than " + stripEnginePrefix(bestKey))
+			continue
 		}
 
+		step.accept("newest orderable generation so far")
 		bestKey = key
 		bestProps = candidateProps
 		bestGen = info.gen
@@ -233,6 +354,55 @@ func (r *RDSRightSize) upgradeGeneration(targetClass string, engine *string, eng
 	return bestKey, bestProps, true
 }
 
+// keyMatchesEngine returns false for engine-prefixed keys of another engine.
+func keyMatchesEngine(key string, engine *string) bool {
+	if engine == nil || *engine == "" {
+		return true
+	}
+	prefix := enginePrefix(key)
+	return prefix == "" || prefix == *engine
+}
+
+// orderable returns true if a class can be ordered in the analyzer's region with
+// the given engine version ("" when unknown).
+func (r *RDSRightSize) orderable(props types.InstanceProperties, engineVersion string) bool {
+	if r.region != "" && !props.AvailableInRegion(r.region) {
+		return false
+	}
+	return r.supportsEngineVersion(props, engineVersion)
+}
+
+// supportsEngineVersion is SupportsEngineVersion, except that an engine version no
+// class lists for the region (a minor version no longer orderable) is only compared
+// with MinEngineVersion, rather than failing every class.
+func (r *RDSRightSize) supportsEngineVersion(props types.InstanceProperties, engineVersion string) bool {
+	if engineVersion != "" && !r.instanceTypes.ListsEngineVersion(r.region, engineVersion) {
+		return props.MeetsMinEngineVersion(engineVersion)
+	}
+	return props.SupportsEngineVersion(r.region, engineVersion)
+}
+
+// scaleUpTarget walks up the instance chain from props to the first class that is
+// orderable for the instance, returning its plain name and properties.
+func (r *RDSRightSize) scaleUpTarget(props types.InstanceProperties, instance *rdsTypes.Instance, trace *decisionTrace) (string, types.InstanceProperties, bool) {
+	engineVersion := aws.ToString(instance.EngineVersion)
+	for candidateName := props.Up; candidateName != nil; {
+		step := trace.candidate(types.ScaleUpStage, *candidateName)
+		candidate, exists := r.lookupInstanceProperties(*candidateName, instance.Engine)
+		if !step.check(checkInstanceTypes, exists, "") {
+			step.reject("not in the instance types")
+			break
+		}
+		if r.checkOrderable(step, candidate, engineVersion) {
+			step.accept("next larger orderable class")
+			return stripEnginePrefix(*candidateName), candidate, true
+		}
+		step.reject("not orderable")
+		candidateName = candidate.Up
+	}
+	return "", types.InstanceProperties{}, false
+}
+
 // tryUpgradeGeneration attempts to upgrade the target instance to a newer generation.
 // If successful, it updates the recommendation's target fields and recalculates cost diff.
 // For downscale recommendations, it also re-validates projected CPU and bandwidth constraints.
@@ -254,48 +424,27 @@ func (r *RDSRightSize) tryUpgradeRecommendation(
 		engineVersion = *instance.EngineVersion
 	}
 
-	newKey, newProps, found := r.upgradeGeneration(*rec.RecommendedInstanceType, instance.Engine, engineVersion)
+	trace := r.newTrace()
+	defer func() {
+		rec.DecisionTrace = append(rec.DecisionTrace, trace.Steps()...)
+	}()
+
+	newKey, newProps, found := r.upgradeGeneration(*rec.RecommendedInstanceType, instance.Engine, engineVersion, trace)
 	if !found {
 		return
 	}
-
-	// For downscale: re-validate constraints against the new generation target
-	if rec.Recommendation == types.DownScale {
-		// Bandwidth constraint
-		if newProps.MaxBandwidth != nil && bandwidthTotal != nil {
-			if *bandwidthTotal >= float64(*newProps.MaxBandwidth*mbit_bytes) {
-				return // newer gen can't handle bandwidth
-			}
-		}
-
-		// Projected CPU constraint
-		if cpuValue != nil && currentProps != nil && currentProps.Vcpu > 0 && newProps.Vcpu > 0 {
-			projectedCPU := *cpuValue * float64(currentProps.Vcpu) / float64(newProps.Vcpu)
-			if projectedCPU > 100 {
-				projectedCPU = 100
-			}
-			if projectedCPU > r.cpuUpsizeThreshold {
-				return // newer gen can't handle CPU
-			}
-		}
-	}
-
-	// For upscale: verify the newer gen target has >= the original target's capacity
-	if rec.Recommendation == types.UpScale {
-		if rec.TargetInstanceProperties != nil {
-			if newProps.Vcpu < rec.TargetInstanceProperties.Vcpu ||
-				newProps.Mem < rec.TargetInstanceProperties.Mem {
-				return // newer gen has less capacity — don't downgrade
-			}
-		}
+	step := trace.candidate(types.GenerationUpgradeStage, newKey)
+	if !r.checkReplacementTarget(step, rec, currentProps, newProps, cpuValue, bandwidthTotal) {
+		return
 	}
+	step.accept("replaces " + *rec.RecommendedInstanceType)
 
 	// Apply the upgrade
 	newKeyCopy := stripEnginePrefix(newKey)
 	newPropsCopy := newProps
 	rec.RecommendedInstanceType = &newKeyCopy
 	rec.TargetInstanceProperties = &newPropsCopy
-	rec.MonthlyApproximatePriceDiff = Float64((newProps.GetPrice(r.region) - currentProps.GetPrice(r.region)) * hours_month)
+	rec.MonthlyApproximatePriceDiff = Float64((r.price(newProps, instance) - r.price(*currentProps, instance)) * hours_month)
 
 	// Re-check connections soft constraint for downscale.
 	// Only reset/re-evaluate when peakConns is provided; when nil (e.g., post-equalization
@@ -313,13 +462,63 @@ func (r *RDSRightSize) tryUpgradeRecommendation(
 	}
 }
 
+// checkReplacementTarget checks a class replacing the target of a scaling
+// recommendation, recording the checks on step and rejecting it when one fails.
+// A downscale replacement must sustain the current throughput (when its bandwidth
+// is known) and keep projected CPU within the upsize threshold; an upscale
+// replacement needs at least the capacity of the original target.
+func (r *RDSRightSize) checkReplacementTarget(
+	step *traceStep,
+	rec *types.Recommendation,
+	currentProps *types.InstanceProperties,
+	newProps types.InstanceProperties,
+	cpuValue *float64,
+	bandwidthTotal *float64,
+) bool {
+	if rec.Recommendation == types.DownScale {
+		// Bandwidth constraint
+		if newProps.MaxBandwidth != nil && bandwidthTotal != nil {
+			if !step.check(checkBandwidth, *bandwidthTotal < float64(*newProps.MaxBandwidth*mbit_bytes), bandwidthValue(*bandwidthTotal, newProps.MaxBandwidth)) {
+				step.reject("cannot sustain the current throughput")
+				return false
+			}
+		}
+
+		// Projected CPU constraint
+		if cpuValue != nil && currentProps != nil && currentProps.Vcpu > 0 && newProps.Vcpu > 0 {
+			projectedCPU := *cpuValue * float64(currentProps.Vcpu) / float64(newProps.Vcpu)
+			if projectedCPU > 100 {
+				projectedCPU = 100
+			}
+			if !step.check(checkProjectedCPU, projectedCPU <= r.cpuUpsizeThreshold, fmt.Sprintf("%.1f%% (upsize threshold %.0f%%)", projectedCPU, r.cpuUpsizeThreshold)) {
+				step.reject("projected CPU above the upsize threshold")
+				return false
+			}
+		}
+	}
+
+	// For upscale: the replacement must not have less capacity than the original target
+	if rec.Recommendation == types.UpScale && rec.TargetInstanceProperties != nil {
+		if !step.check(checkCapacity, newProps.Vcpu >= rec.TargetInstanceProperties.Vcpu && newProps.Mem >= rec.TargetInstanceProperties.Mem,
+			capacityValue(newProps, *rec.TargetInstanceProperties)) {
+			step.reject("less capacity than the scaling target")
+			return false
+		}
+	}
+	return true
+}
+
 // DoAnalyzeRDS is the original CLI entry point. It runs the analysis and writes
 // results to a JSON file and prints cost summary to stdout.
-func (r *RDSRightSize) DoAnalyzeRDS() error {
-	opts := &AnalysisOptions{
-		OnWarning: func(instanceId, msg string) {
+// If opts is nil, defaults are used; a nil OnWarning prints skipped instances to stderr.
+func (r *RDSRightSize) DoAnalyzeRDS(opts *AnalysisOptions) error {
+	if opts == nil {
+		opts = &AnalysisOptions{}
+	}
+	if opts.OnWarning == nil {
+		opts.OnWarning = func(instanceId, msg string) {
 			fmt.Fprintf(os.Stderr, "Warning: skipping instance %s: %s\n", instanceId, msg)
-		},
+		}
 	}
 	recommendations, err := r.AnalyzeRDS(context.Background(), opts)
 	if err != nil {
@@ -340,6 +539,23 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 	if opts == nil {
 		opts = &AnalysisOptions{}
 	}
+	r.explain = opts.Explain
+
+	minWindowDays := opts.MinWindowDays
+	if minWindowDays <= 0 {
+		minWindowDays = DefaultMinWindowDays
+	}
+
+	idleThresholds := types.DefaultIdleThresholds()
+	if opts.IdleThresholds != nil {
+		idleThresholds = *opts.IdleThresholds
+	}
+
+	if len(opts.CURCosts) > 0 {
+		if err := CheckCURCalibration(r.pricingModel, r.pricingOverrides); err != nil {
+			return nil, err
+		}
+	}
 
 	warn := func(instanceId, msg string) {
 		if opts.OnWarning != nil {
@@ -348,18 +564,56 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 	}
 
 	recommendations := make([]types.Recommendation, 0)
+
+	// Measured metrics per instance, attached to all of its recommendations
+	measuredByInstance := make(map[string]*types.RecommendationMetrics)
+	confidenceByInstance := make(map[string]confidence)
+	runningByInstance := make(map[string]string)
+	runningVersionByInstance := make(map[string]string)
+	now := time.Now()
+
+	// Optimized and Skipped instances are kept aside until cluster equalization has
+	// decided which instances change
+	var inventory []types.Recommendation
+	skip := func(instance rdsTypes.Instance, msg string) {
+		if opts.IncludeOptimized {
+			inventory = append(inventory, types.Recommendation{
+				Instance:       instance,
+				Recommendation: types.Skipped,
+				Reason:         types.AnalysisSkippedReason,
+				SkipReason:     msg,
+				Metrics:        measuredByInstance[*instance.DBInstanceIdentifier],
+			})
+		}
+	}
+
 	instances, err := r.rds.GetInstances(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter instances by tags first to get accurate total count
+	if err := r.setClusterStorageTypes(ctx, instances); err != nil {
+		return nil, err
+	}
+
+	if err := r.setClassChanges(ctx, instances); err != nil {
+		return nil, err
+	}
+
+	// Filter instances by tags first to get accurate total count. Instances that
+	// are stopped or changing are reported with their status instead of analyzed.
 	filteredInstances := make([]rdsTypes.Instance, 0)
+	var unavailable []types.Recommendation
 	for _, instance := range instances {
 		requiredTags := r.hasRequiredTags(&instance)
-		if *requiredTags {
-			filteredInstances = append(filteredInstances, instance)
+		if !*requiredTags {
+			continue
+		}
+		if !analyzable(&instance) {
+			unavailable = append(unavailable, unavailableRecommendation(instance))
+			continue
 		}
+		filteredInstances = append(filteredInstances, instance)
 	}
 
 	total := len(filteredInstances)
@@ -372,11 +626,22 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 			opts.OnProgress(i+1, total, *instance.DBInstanceIdentifier)
 		}
 
-		metrics, err := r.getMetrics(ctx, &instance)
+		metrics, err := r.getMetrics(ctx, &instance, time.Time{})
 		if err != nil {
 			return nil, err
 		}
 
+		// Statistics spanning an instance class change mix two capacities, so only
+		// the data points after the most recent change are used
+		classChange, classChangeSource := r.lastClassChange(&instance, metrics, now)
+		if classChange != nil {
+			instance.LastClassChange = classChange
+			metrics, err = r.getMetrics(ctx, &instance, *classChange)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		// Optionally fetch time-series metrics for graphs
 		var tsMetrics *cwTypes.TimeSeriesMetrics
 		if opts.FetchTimeSeries {
@@ -387,6 +652,24 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 			}
 		}
 
+		// Measured metrics relate to the class the instance runs today
+		measured := r.measuredMetrics(ctx, metrics, &instance)
+		if classChange != nil {
+			measured.ClassChangedAt = classChange
+			measured.ClassChangeSource = classChangeSource
+		}
+		measuredByInstance[*instance.DBInstanceIdentifier] = measured
+
+		// An instance scheduled to change class is analyzed as it will run
+		runningClass := r.applyPendingClass(&instance, metrics)
+		if runningClass != "" {
+			runningByInstance[*instance.DBInstanceIdentifier] = runningClass
+		}
+		if runningVersion := applyPendingEngineVersion(&instance); runningVersion != "" {
+			runningVersionByInstance[*instance.DBInstanceIdentifier] = runningVersion
+		}
+		confidenceByInstance[*instance.DBInstanceIdentifier] = r.confidence(&instance, metrics, runningClass, now)
+
 		// Track data for cluster equalization
 		prevRecLen := len(recommendations)
 		var instanceProps *types.InstanceProperties
@@ -395,26 +678,39 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 			cpuValue = cpuMetric.Value
 		}
 		peakConns := r.getPeakConnections(metrics)
+		optimizedReason := types.WithinThresholdsReason
 
 		noConnections, err := r.hadNoConnections(metrics)
 		if err != nil {
 			warn(*instance.DBInstanceIdentifier, err.Error())
+			skip(instance, err.Error())
 			continue
 		}
 
-		if *noConnections {
+		// Connections held only by monitoring agents do not make an instance used
+		var idleEvidence []types.IdleSignal
+		if !*noConnections {
+			idleEvidence = likelyIdle(metrics, idleThresholds)
+		}
+
+		if *noConnections || idleEvidence != nil {
 			terminateRec := types.Recommendation{
 				Instance:          instance,
 				Recommendation:    types.Terminate,
 				Reason:            types.NoUsageWithinPeriodReason,
 				TimeSeriesMetrics: tsMetrics,
 			}
+			if idleEvidence != nil {
+				terminateRec.Recommendation = types.LikelyIdle
+				terminateRec.Reason = types.MonitoringOnlyReason
+				terminateRec.IdleEvidence = idleEvidence
+			}
 			// Look up current instance properties so we can compute the cost of termination
 			if termProps, ok := r.lookupInstanceProperties(*instance.DBInstanceClass, instance.Engine); ok {
 				instanceProps = &termProps
 				terminateRec.CurrentInstanceProperties = &termProps
 				// Terminating saves the full current cost (target cost is $0)
-				terminateRec.MonthlyApproximatePriceDiff = Float64(-termProps.GetPrice(r.region) * hours_month)
+				terminateRec.MonthlyApproximatePriceDiff = Float64(-r.price(termProps, &instance) * hours_month)
 			}
 			recommendations = append(recommendations, terminateRec)
 		} else {
@@ -426,49 +722,61 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 				memoryUtilization, err := r.getMemoryUtilization(metrics, &instanceProperties)
 				if err != nil {
 					warn(*instance.DBInstanceIdentifier, err.Error())
+					skip(instance, err.Error())
 					continue
 				}
 
-				if *memoryUtilization.UnderProvisioned && instanceProperties.Up != nil {
-					upInstance, _ := r.lookupInstanceProperties(*instanceProperties.Up, instance.Engine)
-					upName := stripEnginePrefix(*instanceProperties.Up)
+				upTrace := r.newTrace()
+				upName, upInstance, hasUp := r.scaleUpTarget(instanceProperties, &instance, upTrace)
+
+				if *memoryUtilization.UnderProvisioned && hasUp {
 					recommendations = append(recommendations, types.Recommendation{
 						Instance:                    instance,
 						Recommendation:              types.UpScale,
 						Reason:                      types.MemoryUnderProvisionedReason,
 						RecommendedInstanceType:     &upName,
 						MetricValue:                 memoryUtilization.Value,
-						MonthlyApproximatePriceDiff: Float64((upInstance.GetPrice(r.region) - instanceProperties.GetPrice(r.region)) * hours_month),
+						MonthlyApproximatePriceDiff: Float64((r.price(upInstance, &instance) - r.price(instanceProperties, &instance)) * hours_month),
 						CurrentInstanceProperties:   &instanceProperties,
 						TargetInstanceProperties:    &upInstance,
 						TimeSeriesMetrics:           tsMetrics,
+						DecisionTrace:               upTrace.Steps(),
 					})
 				} else {
+					if *memoryUtilization.UnderProvisioned {
+						optimizedReason = types.NoScalingTargetReason
+					}
+
 					cpuUtilization, err := r.getCPUUtilization(metrics)
 					if err != nil {
 						warn(*instance.DBInstanceIdentifier, err.Error())
+						skip(instance, err.Error())
 						continue
 					}
 
 					bandwidthUtilization, err := r.getBandwidthUtilization(metrics, &instanceProperties)
 					if err != nil {
 						warn(*instance.DBInstanceIdentifier, err.Error())
+						skip(instance, err.Error())
 						continue
 					}
+					if cpuUtilization.Status == types.CPUUnderProvisioned ||
+						(cpuUtilization.Status == types.CPUOverProvisioned && bandwidthUtilization.Status != types.BandwidthUnderProvisioned) {
+						optimizedReason = types.NoScalingTargetReason
+					}
 
-					if cpuUtilization.Status == types.CPUUnderProvisioned && instanceProperties.Up != nil {
-						upInstance, _ := r.lookupInstanceProperties(*instanceProperties.Up, instance.Engine)
-						cpuUpName := stripEnginePrefix(*instanceProperties.Up)
+					if cpuUtilization.Status == types.CPUUnderProvisioned && hasUp {
 						recommendations = append(recommendations, types.Recommendation{
 							Instance:                    instance,
 							Recommendation:              types.UpScale,
 							Reason:                      types.CPUUnderProvisionedReason,
-							RecommendedInstanceType:     &cpuUpName,
+							RecommendedInstanceType:     &upName,
 							MetricValue:                 cpuUtilization.Value,
-							MonthlyApproximatePriceDiff: Float64((upInstance.GetPrice(r.region) - instanceProperties.GetPrice(r.region)) * hours_month),
+							MonthlyApproximatePriceDiff: Float64((r.price(upInstance, &instance) - r.price(instanceProperties, &instance)) * hours_month),
 							CurrentInstanceProperties:   &instanceProperties,
 							TargetInstanceProperties:    &upInstance,
 							TimeSeriesMetrics:           tsMetrics,
+							DecisionTrace:               upTrace.Steps(),
 						})
 					} else if cpuUtilization.Status == types.CPUOverProvisioned && bandwidthUtilization.Status != types.BandwidthUnderProvisioned && instanceProperties.Down != nil {
 						// Walk down the instance chain to find the optimal (smallest) downscale target
@@ -476,22 +784,28 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 
 						var bestDown *string
 						var bestDownInstance *types.InstanceProperties
+						downTrace := r.newTrace()
 
 						candidateName := instanceProperties.Down
 						for candidateName != nil {
+							step := downTrace.candidate(types.ScaleDownStage, *candidateName)
 							candidate, exists := r.lookupInstanceProperties(*candidateName, instance.Engine)
-							if !exists {
+							if !step.check(checkInstanceTypes, exists, "") {
+								step.reject("not in the instance types")
 								break
 							}
 
-							// Skip candidates not available in the user's region
-							if r.region != "" && !candidate.AvailableInRegion(r.region) {
+							// Skip candidates not orderable in the user's region with its engine version
+							if !r.checkOrderable(step, candidate, aws.ToString(instance.EngineVersion)) {
+								step.reject("not orderable; trying the next smaller class")
 								candidateName = candidate.Down
 								continue
 							}
 
 							// Hard constraint: bandwidth — target must handle current throughput
-							if candidate.MaxBandwidth == nil || *bandwidthUtilization.Total >= float64(*candidate.MaxBandwidth*mbit_bytes) {
+							bandwidthOK := candidate.MaxBandwidth != nil && *bandwidthUtilization.Total < float64(*candidate.MaxBandwidth*mbit_bytes)
+							if !step.check(checkBandwidth, bandwidthOK, bandwidthValue(*bandwidthUtilization.Total, candidate.MaxBandwidth)) {
+								step.reject("cannot sustain the current throughput")
 								break
 							}
 
@@ -500,7 +814,8 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 							if projectedCPU > 100 {
 								projectedCPU = 100
 							}
-							if projectedCPU > r.cpuUpsizeThreshold {
+							if !step.check(checkProjectedCPU, projectedCPU <= r.cpuUpsizeThreshold, fmt.Sprintf("%.1f%% (upsize threshold %.0f%%)", projectedCPU, r.cpuUpsizeThreshold)) {
+								step.reject("projected CPU above the upsize threshold")
 								break
 							}
 
@@ -512,10 +827,12 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 
 							// If projected CPU is in the optimized zone, this is the ideal target
 							if projectedCPU >= r.cpuDownsizeThreshold {
+								step.accept(fmt.Sprintf("projected CPU at or above the downsize threshold (%.0f%%)", r.cpuDownsizeThreshold))
 								break
 							}
 
 							// Still over-provisioned on this candidate — try even smaller
+							step.accept(fmt.Sprintf("still below the downsize threshold (%.0f%%); trying the next smaller class", r.cpuDownsizeThreshold))
 							candidateName = candidate.Down
 						}
 
@@ -526,10 +843,11 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 								Reason:                      types.CPUOverProvisionedReason,
 								RecommendedInstanceType:     bestDown,
 								MetricValue:                 cpuUtilization.Value,
-								MonthlyApproximatePriceDiff: Float64((bestDownInstance.GetPrice(r.region) - instanceProperties.GetPrice(r.region)) * hours_month),
+								MonthlyApproximatePriceDiff: Float64((r.price(*bestDownInstance, &instance) - r.price(instanceProperties, &instance)) * hours_month),
 								CurrentInstanceProperties:   &instanceProperties,
 								TargetInstanceProperties:    bestDownInstance,
 								TimeSeriesMetrics:           tsMetrics,
+								DecisionTrace:               downTrace.Steps(),
 							}
 
 							// Soft constraint: connections warning
@@ -545,9 +863,28 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 						}
 					}
 				}
+			} else {
+				skip(instance, fmt.Sprintf("instance class %s not found in instance types", *instance.DBInstanceClass))
 			}
 		}
 
+		// Previous-generation classes are migrated even when utilization is optimized
+		if !*noConnections && instanceProps != nil {
+			recommendations = r.migratePreviousGeneration(recommendations, len(recommendations) > prevRecLen, instance, instanceProps, metrics, cpuValue, tsMetrics)
+		}
+
+		if opts.IncludeOptimized && !*noConnections && instanceProps != nil && len(recommendations) == prevRecLen {
+			inventory = append(inventory, types.Recommendation{
+				Instance:                  instance,
+				Recommendation:            types.Optimized,
+				Reason:                    optimizedReason,
+				MetricValue:               cpuValue,
+				Metrics:                   measured,
+				CurrentInstanceProperties: instanceProps,
+				TimeSeriesMetrics:         tsMetrics,
+			})
+		}
+
 		// Collect cluster data for equalization
 		if instance.DBClusterIdentifier != nil && *instance.DBClusterIdentifier != "" {
 			clusterID := *instance.DBClusterIdentifier
@@ -575,6 +912,8 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 
 	// Equalize recommendations within clusters
 	recommendations = r.equalizeClusterRecommendations(ctx, recommendations, clusterData)
+	recommendations = appendUnchanged(recommendations, inventory)
+	recommendations = append(recommendations, unavailable...)
 
 	// Upgrade non-cluster recommendations to newer instance generations.
 	// Cluster recs are already gen-upgraded inside equalizeClusterRecommendations,
@@ -585,11 +924,25 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 	if r.preferNewGen {
 		for i := range recommendations {
 			rec := &recommendations[i]
-			if rec.Recommendation == types.Terminate || rec.CurrentInstanceProperties == nil || rec.ClusterEqualized {
+			if rec.Recommendation.RemovesInstance() || rec.CurrentInstanceProperties == nil || rec.ClusterEqualized {
 				continue
 			}
 			r.tryUpgradeRecommendation(ctx, rec, rec.CurrentInstanceProperties, &rec.Instance, rec.MetricValue, nil, rec.PeakConnections)
 		}
+
+		// Report newer generations only the instance's engine version rules out
+		for i := range recommendations {
+			recommendations[i].BlockedGenerationUpgrade = r.blockedGenerationUpgrade(&recommendations[i])
+		}
+	}
+
+	// Compare Aurora Standard and I/O-Optimized storage for each analyzed cluster
+	if opts.StorageAnalysis {
+		storageRecs, err := r.analyzeClusterStorage(ctx, opts.Cache, instances, filteredInstances, warn)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, storageRecs...)
 	}
 
 	// Sort recommendations: cluster members grouped together, then by instance ID
@@ -598,7 +951,7 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 	// Compute projected CPU for all non-Terminate recommendations
 	for i := range recommendations {
 		rec := &recommendations[i]
-		if rec.Recommendation != types.Terminate &&
+		if !rec.Recommendation.RemovesInstance() &&
 			rec.MetricValue != nil &&
 			rec.CurrentInstanceProperties != nil &&
 			rec.TargetInstanceProperties != nil &&
@@ -613,9 +966,111 @@ func (r *RDSRightSize) AnalyzeRDS(ctx context.Context, opts *AnalysisOptions) ([
 		}
 	}
 
+	for i := range recommendations {
+		recommendations[i].PricingModel = r.pricingModel
+	}
+
+	// Account for reserved instance coverage across all instances in the region
+	if opts.ReservedCoverage {
+		reservations, err := r.rds.GetReservedInstances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe reserved DB instances: %w", err)
+		}
+		r.applyReservedCoverage(recommendations, instances, reservations)
+	}
+
+	// Calibrate list-price estimates against billed costs
+	if len(opts.CURCosts) > 0 {
+		applyCURCalibration(recommendations, opts.CURCosts)
+	}
+
+	applyCurrency(recommendations, opts.Currency)
+
+	// Trace every result back to the instance types (and pricing snapshot) used
+	for i := range recommendations {
+		rec := &recommendations[i]
+		rec.InstanceTypesProvenance = r.provenance
+		if rec.Metrics == nil && rec.DBInstanceIdentifier != nil {
+			rec.Metrics = measuredByInstance[*rec.DBInstanceIdentifier]
+		}
+		if running, ok := runningByInstance[ptr.ToString(rec.DBInstanceIdentifier)]; ok {
+			rec.RunningInstanceClass = &running
+		}
+		if running, ok := runningVersionByInstance[ptr.ToString(rec.DBInstanceIdentifier)]; ok {
+			rec.RunningEngineVersion = &running
+		}
+		if rec.Metrics != nil && rec.Metrics.ClassChangedAt != nil && rec.Metrics.WindowDays < float64(minWindowDays) {
+			rec.ShortMetricsWindow = true
+		}
+		if c, ok := confidenceByInstance[ptr.ToString(rec.DBInstanceIdentifier)]; ok {
+			score := c.score
+			rec.Confidence = &score
+			rec.ConfidenceReasons = c.reasons
+		}
+		if rec.CurrentInstanceProperties != nil && rec.CurrentInstanceProperties.PreviousGeneration {
+			rec.PreviousGeneration = true
+			rec.EndOfLife = rec.CurrentInstanceProperties.EndOfLife
+		}
+		rec.OnDemandPriceFallback = r.onDemandPriceFallback(rec)
+	}
+
+	if opts.MinConfidence > 0 {
+		recommendations = filterByConfidence(recommendations, opts.MinConfidence)
+	}
+
 	return recommendations, nil
 }
 
+// appendUnchanged adds the Optimized and Skipped recommendations of instances that
+// are left without any other recommendation (e.g. after cluster equalization).
+func appendUnchanged(recommendations []types.Recommendation, unchanged []types.Recommendation) []types.Recommendation {
+	changed := make(map[string]bool, len(recommendations))
+	for _, rec := range recommendations {
+		if rec.DBInstanceIdentifier != nil {
+			changed[*rec.DBInstanceIdentifier] = true
+		}
+	}
+	for _, rec := range unchanged {
+		if rec.DBInstanceIdentifier != nil && changed[*rec.DBInstanceIdentifier] {
+			continue
+		}
+		recommendations = append(recommendations, rec)
+	}
+	return recommendations
+}
+
+// setClusterStorageTypes records each cluster member's storage type so costs
+// use the I/O-Optimized rate where it applies. Clusters are only described
+// when at least one instance belongs to a cluster.
+func (r *RDSRightSize) setClusterStorageTypes(ctx context.Context, instances []rdsTypes.Instance) error {
+	hasClusters := false
+	for _, instance := range instances {
+		if instance.DBClusterIdentifier != nil && *instance.DBClusterIdentifier != "" {
+			hasClusters = true
+			break
+		}
+	}
+	if !hasClusters {
+		return nil
+	}
+
+	storageTypes, err := r.rds.GetClusterStorageTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to describe DB clusters: %w", err)
+	}
+
+	for i := range instances {
+		if instances[i].DBClusterIdentifier == nil {
+			continue
+		}
+		if storageType, ok := storageTypes[*instances[i].DBClusterIdentifier]; ok {
+			storageTypeCopy := storageType
+			instances[i].StorageType = &storageTypeCopy
+		}
+	}
+	return nil
+}
+
 // equalizeClusterRecommendations adjusts recommendations so all instances in the same
 // Aurora cluster share a single target instance type. The cluster target is the largest
 // (by vCPU, then memory) among all members' ideal types:
@@ -639,7 +1094,7 @@ func (r *RDSRightSize) equalizeClusterRecommendations(
 		allTerminate := true
 		for _, m := range members {
 			if m.recIndex >= 0 {
-				if recommendations[m.recIndex].Recommendation != types.Terminate {
+				if !recommendations[m.recIndex].Recommendation.RemovesInstance() {
 					allTerminate = false
 					break
 				}
@@ -657,13 +1112,14 @@ func (r *RDSRightSize) equalizeClusterRecommendations(
 		// (the largest by vCPU, then memory)
 		var clusterTarget string
 		var clusterTargetProps types.InstanceProperties
+		clusterTrace := r.newTrace()
 
 		for _, m := range members {
 			var idealType string
 
 			if m.recIndex >= 0 {
 				rec := &recommendations[m.recIndex]
-				if rec.Recommendation == types.Terminate {
+				if rec.Recommendation.RemovesInstance() {
 					// In a non-all-terminate cluster, treat as wanting current size
 					if rec.DBInstanceClass != nil {
 						idealType = *rec.DBInstanceClass
@@ -682,16 +1138,22 @@ func (r *RDSRightSize) equalizeClusterRecommendations(
 				continue
 			}
 
+			step := clusterTrace.candidate(types.ClusterEqualizationStage, idealType)
 			idealProps, exists := r.lookupInstanceProperties(idealType, m.instance.Engine)
-			if !exists {
+			if !step.check(checkInstanceTypes, exists, "") {
+				step.reject("not in the instance types")
 				continue
 			}
 
-			if clusterTarget == "" ||
+			memberValue := fmt.Sprintf("%d vCPU, %d GiB, ideal for %s", idealProps.Vcpu, idealProps.Mem, aws.ToString(m.instance.DBInstanceIdentifier))
+			if step.check(checkCapacity, clusterTarget == "" ||
 				idealProps.Vcpu > clusterTargetProps.Vcpu ||
-				(idealProps.Vcpu == clusterTargetProps.Vcpu && idealProps.Mem > clusterTargetProps.Mem) {
+				(idealProps.Vcpu == clusterTargetProps.Vcpu && idealProps.Mem > clusterTargetProps.Mem), memberValue) {
+				step.accept("largest member target so far")
 				clusterTarget = idealType
 				clusterTargetProps = idealProps
+			} else {
+				step.reject("smaller than " + stripEnginePrefix(clusterTarget))
 			}
 		}
 
@@ -699,6 +1161,17 @@ func (r *RDSRightSize) equalizeClusterRecommendations(
 			continue
 		}
 
+		// Never equalize onto a previous-generation class (e.g. a member kept at its current size)
+		if clusterTargetProps.PreviousGeneration {
+			if name, props, found := r.currentGenerationEquivalent(clusterTarget, clusterTargetProps, &members[0].instance); found {
+				step := clusterTrace.candidate(types.ClusterEqualizationStage, name)
+				step.check(checkCapacity, true, capacityValue(props, clusterTargetProps))
+				step.accept("current-generation equivalent of " + stripEnginePrefix(clusterTarget))
+				clusterTarget = name
+				clusterTargetProps = props
+			}
+		}
+
 		// Only upgrade generation if the pre-upgrade cluster target actually requires
 		// changes for at least one member. This prevents spurious generation-only
 		// recommendations when equalization would otherwise be a no-op (e.g., all
@@ -731,11 +1204,15 @@ func (r *RDSRightSize) equalizeClusterRecommendations(
 					break
 				}
 			}
-			if newKey, newProps, found := r.upgradeGeneration(clusterTarget, clusterEngine, clusterEngineVersion); found {
+			if newKey, newProps, found := r.upgradeGeneration(clusterTarget, clusterEngine, clusterEngineVersion, clusterTrace); found {
 				// Only upgrade if the new gen has at least the same capacity
-				if newProps.Vcpu >= clusterTargetProps.Vcpu && newProps.Mem >= clusterTargetProps.Mem {
+				step := clusterTrace.candidate(types.ClusterEqualizationStage, newKey)
+				if step.check(checkCapacity, newProps.Vcpu >= clusterTargetProps.Vcpu && newProps.Mem >= clusterTargetProps.Mem, capacityValue(newProps, clusterTargetProps)) {
+					step.accept("newer generation of " + stripEnginePrefix(clusterTarget))
 					clusterTarget = newKey
 					clusterTargetProps = newProps
+				} else {
+					step.reject("less capacity than " + stripEnginePrefix(clusterTarget))
 				}
 			}
 		}
@@ -786,18 +1263,21 @@ func (r *RDSRightSize) equalizeClusterRecommendations(
 				rec.RecommendedInstanceType = &targetName
 				rec.CurrentInstanceProperties = currentProps
 				rec.TargetInstanceProperties = &targetPropsCopy
-				rec.MonthlyApproximatePriceDiff = Float64((targetPropsCopy.GetPrice(r.region) - currentProps.GetPrice(r.region)) * hours_month)
+				rec.MonthlyApproximatePriceDiff = Float64((r.price(targetPropsCopy, &m.instance) - r.price(*currentProps, &m.instance)) * hours_month)
 				rec.ClusterEqualized = true
+				rec.DecisionTrace = append(rec.DecisionTrace, clusterTrace.Steps()...)
 
 				// Set MetricValue to CPU for projected CPU calculation if not already set
 				if rec.MetricValue == nil && m.cpuValue != nil {
 					rec.MetricValue = m.cpuValue
 				}
 
-				// Override TERMINATE reason
-				if rec.Reason == types.NoUsageWithinPeriodReason {
+				// Override TERMINATE and LikelyIdle reasons; the idle evidence no longer
+				// backs the recommendation
+				if rec.Reason == types.NoUsageWithinPeriodReason || rec.Reason == types.MonitoringOnlyReason {
 					rec.Reason = types.ClusterEqualizationReason
 				}
+				rec.IdleEvidence = nil
 
 				// Recalculate connections warning
 				rec.MaxConnectionsAdjustRequired = false
@@ -817,11 +1297,12 @@ func (r *RDSRightSize) equalizeClusterRecommendations(
 					Reason:                      types.ClusterEqualizationReason,
 					RecommendedInstanceType:     &targetName,
 					MetricValue:                 m.cpuValue,
-					MonthlyApproximatePriceDiff: Float64((targetPropsCopy.GetPrice(r.region) - currentProps.GetPrice(r.region)) * hours_month),
+					MonthlyApproximatePriceDiff: Float64((r.price(targetPropsCopy, &m.instance) - r.price(*currentProps, &m.instance)) * hours_month),
 					CurrentInstanceProperties:   currentProps,
 					TargetInstanceProperties:    &targetPropsCopy,
 					TimeSeriesMetrics:           m.tsMetrics,
 					ClusterEqualized:            true,
+					DecisionTrace:               append([]types.DecisionStep(nil), clusterTrace.Steps()...),
 				}
 
 				// Connections warning for downscale
@@ -866,31 +1347,51 @@ func CalculateCostDifference(recommendations []types.Recommendation) float64 {
 	return priceDiff
 }
 
-// CostBreakdown separates scaling (upscale/downscale) costs from terminate costs.
+// CostBreakdown separates scaling (upscale/downscale) costs from terminate and
+// storage configuration costs.
 type CostBreakdown struct {
-	ScalingMonthly  float64 // UPSCALE + DOWNSCALE price diffs only
-	TotalMonthly    float64 // All recommendations including TERMINATE
-	HasTerminations bool    // Whether any TERMINATE recs contributed
+	ScalingMonthly   float64 // UPSCALE + DOWNSCALE price diffs only
+	StorageMonthly   float64 // Storage configuration (Standard vs I/O-Optimized) price diffs
+	TotalMonthly     float64 // All recommendations including TERMINATE
+	HasTerminations  bool    // Whether any TERMINATE or LIKELY IDLE recs contributed
+	HasStorage       bool    // Whether any storage configuration recs contributed
+	EffectiveMonthly float64 // Total after reserved instance coverage (on-demand diff when not covered)
+	HasEffective     bool    // Whether any rec carried a reserved-coverage effective diff
 }
 
 // Yearly returns the yearly equivalents.
 func (cb CostBreakdown) ScalingYearly() float64 { return cb.ScalingMonthly * 12 }
 func (cb CostBreakdown) TotalYearly() float64   { return cb.TotalMonthly * 12 }
 
+// add accumulates a single recommendation's price differences into the breakdown.
+func (cb *CostBreakdown) add(rec types.Recommendation) {
+	if rec.MonthlyApproximatePriceDiff == nil {
+		return
+	}
+	diff := *rec.MonthlyApproximatePriceDiff
+	cb.TotalMonthly += diff
+	switch {
+	case rec.Recommendation.RemovesInstance():
+		cb.HasTerminations = true
+	case rec.Recommendation == types.StorageConfiguration:
+		cb.StorageMonthly += diff
+		cb.HasStorage = true
+	default:
+		cb.ScalingMonthly += diff
+	}
+	if rec.EffectiveMonthlyPriceDiff != nil {
+		cb.EffectiveMonthly += *rec.EffectiveMonthlyPriceDiff
+		cb.HasEffective = true
+	} else {
+		cb.EffectiveMonthly += diff
+	}
+}
+
 // CalculateCostBreakdown computes monthly cost differences split by scaling vs terminate.
 func CalculateCostBreakdown(recommendations []types.Recommendation) CostBreakdown {
 	var cb CostBreakdown
 	for _, rec := range recommendations {
-		if rec.MonthlyApproximatePriceDiff == nil {
-			continue
-		}
-		diff := *rec.MonthlyApproximatePriceDiff
-		cb.TotalMonthly += diff
-		if rec.Recommendation == types.Terminate {
-			cb.HasTerminations = true
-		} else {
-			cb.ScalingMonthly += diff
-		}
+		cb.add(rec)
 	}
 	return cb
 }
@@ -898,37 +1399,49 @@ func CalculateCostBreakdown(recommendations []types.Recommendation) CostBreakdow
 // CalculateRegionalCostBreakdown computes cost breakdowns grouped by region.
 // Returns a map of region -> CostBreakdown, and a sorted slice of region names.
 func CalculateRegionalCostBreakdown(recommendations []types.Recommendation) (map[string]CostBreakdown, []string) {
-	byRegion := make(map[string]CostBreakdown)
+	return calculateGroupedCostBreakdown(recommendations, func(rec types.Recommendation) string {
+		return rec.Region
+	})
+}
+
+// CalculateAccountCostBreakdown computes cost breakdowns grouped by AWS account.
+// Returns a map of account ID -> CostBreakdown, and a sorted slice of account IDs.
+func CalculateAccountCostBreakdown(recommendations []types.Recommendation) (map[string]CostBreakdown, []string) {
+	return calculateGroupedCostBreakdown(recommendations, func(rec types.Recommendation) string {
+		return rec.AccountId
+	})
+}
+
+// calculateGroupedCostBreakdown computes cost breakdowns grouped by key, skipping
+// recommendations with an empty key.
+func calculateGroupedCostBreakdown(recommendations []types.Recommendation, key func(types.Recommendation) string) (map[string]CostBreakdown, []string) {
+	byKey := make(map[string]CostBreakdown)
 	for _, rec := range recommendations {
-		region := rec.Region
-		if region == "" {
+		k := key(rec)
+		if k == "" {
 			continue
 		}
-		cb := byRegion[region]
-		if rec.MonthlyApproximatePriceDiff != nil {
-			diff := *rec.MonthlyApproximatePriceDiff
-			cb.TotalMonthly += diff
-			if rec.Recommendation == types.Terminate {
-				cb.HasTerminations = true
-			} else {
-				cb.ScalingMonthly += diff
-			}
-		}
-		byRegion[region] = cb
+		cb := byKey[k]
+		cb.add(rec)
+		byKey[k] = cb
 	}
 
-	regions := make([]string, 0, len(byRegion))
-	for r := range byRegion {
-		regions = append(regions, r)
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
 	}
-	sort.Strings(regions)
-	return byRegion, regions
+	sort.Strings(keys)
+	return byKey, keys
 }
 
 // SortRecommendations sorts recommendations so Aurora cluster members are grouped together
-// and then ordered by instance ID within each group.
+// and then ordered by instance ID within each group. In multi-account results, accounts
+// are kept together first.
 func SortRecommendations(recs []types.Recommendation) {
 	sort.SliceStable(recs, func(i, j int) bool {
+		if recs[i].AccountId != recs[j].AccountId {
+			return recs[i].AccountId < recs[j].AccountId
+		}
 		ci := recs[i].DBClusterIdentifier
 		cj := recs[j].DBClusterIdentifier
 		if ci != nil && cj == nil {
@@ -945,7 +1458,8 @@ func SortRecommendations(recs []types.Recommendation) {
 		if ii != nil && ij != nil {
 			return *ii < *ij
 		}
-		return false
+		// Cluster-level recommendations (no instance) lead their cluster group
+		return ii == nil && ij != nil
 	})
 }
 
@@ -981,6 +1495,33 @@ func WriteRecommendationsJSON(recommendations []types.Recommendation) (string, e
 	return absPath, nil
 }
 
+// Inventory counts recommendations by outcome when the full inventory is included.
+type Inventory struct {
+	Actionable  int
+	Optimized   int
+	Skipped     int
+	Unavailable int
+}
+
+// CountInventory counts Optimized, Skipped, Unavailable and all other (actionable)
+// recommendations.
+func CountInventory(recommendations []types.Recommendation) Inventory {
+	var inv Inventory
+	for _, rec := range recommendations {
+		switch rec.Recommendation {
+		case types.Optimized:
+			inv.Optimized++
+		case types.Skipped:
+			inv.Skipped++
+		case types.Unavailable:
+			inv.Unavailable++
+		default:
+			inv.Actionable++
+		}
+	}
+	return inv
+}
+
 func Float64(v float64) *float64 {
 	return ptr.Float64(v)
 }
@@ -988,31 +1529,98 @@ func Float64(v float64) *float64 {
 func writeApproximateCostDifference(recommendations []types.Recommendation) {
 	cb := CalculateCostBreakdown(recommendations)
 
+	var c *currency.Currency
+	if len(recommendations) > 0 {
+		c = recommendations[0].Currency
+	}
+
+	if len(recommendations) > 0 && recommendations[0].PricingModel != "" && recommendations[0].PricingModel != types.OnDemand {
+		fmt.Printf("Costs are based on %s pricing\n", recommendations[0].PricingModel)
+		if fallback := OnDemandPriceFallbackCount(recommendations); fallback > 0 {
+			fmt.Printf("  Warning: %d recommendation(s) use on-demand prices for classes without a %s rate\n", fallback, recommendations[0].PricingModel)
+		}
+	}
+	if label := c.Label(); label != "" {
+		fmt.Printf("Costs are in %s\n", label)
+	}
+	if len(recommendations) > 0 && recommendations[0].InstanceTypesProvenance != nil {
+		fmt.Printf("Instance types: %s\n", recommendations[0].InstanceTypesProvenance.Summary())
+	}
+	if inv := CountInventory(recommendations); inv.Optimized > 0 || inv.Skipped > 0 || inv.Unavailable > 0 {
+		fmt.Printf("Inventory: %d recommendation(s) to act on, %d optimized, %d skipped, %d unavailable\n", inv.Actionable, inv.Optimized, inv.Skipped, inv.Unavailable)
+	}
+
 	formatLine := func(label string, monthly float64) string {
 		if monthly > 0 {
-			return fmt.Sprintf("%s: price increase of approximately $%.2f/month ($%.2f/year)", label, monthly, monthly*12)
+			return fmt.Sprintf("%s: price increase of approximately %s/month (%s/year)", label, c.Format(monthly), c.Format(monthly*12))
 		} else if monthly < 0 {
 			savings := monthly * -1
-			return fmt.Sprintf("%s: savings of approximately $%.2f/month ($%.2f/year)", label, savings, savings*12)
+			return fmt.Sprintf("%s: savings of approximately %s/month (%s/year)", label, c.Format(savings), c.Format(savings*12))
 		}
 		return ""
 	}
 
-	if cb.HasTerminations && cb.ScalingMonthly != cb.TotalMonthly {
+	if (cb.HasTerminations || cb.HasStorage) && cb.ScalingMonthly != cb.TotalMonthly {
 		if scalingLine := formatLine("Scaling changes", cb.ScalingMonthly); scalingLine != "" {
 			fmt.Println(scalingLine)
 		} else {
 			fmt.Println("Scaling changes: no cost impact")
 		}
-		if totalLine := formatLine("Total (w/ terminations)", cb.TotalMonthly); totalLine != "" {
+		if cb.HasStorage {
+			if storageLine := formatLine("Storage configuration", cb.StorageMonthly); storageLine != "" {
+				fmt.Println(storageLine)
+			} else {
+				fmt.Println("Storage configuration: no cost impact")
+			}
+		}
+		totalLabel := "Total"
+		if cb.HasTerminations {
+			totalLabel = "Total (w/ terminations)"
+		}
+		if totalLine := formatLine(totalLabel, cb.TotalMonthly); totalLine != "" {
 			fmt.Println(totalLine)
 		}
 	} else {
 		if cb.TotalMonthly > 0 {
-			fmt.Printf("The changes will yield a price increase of approximately $%.2f/month ($%.2f/year)\n", cb.TotalMonthly, cb.TotalMonthly*12)
+			fmt.Printf("The changes will yield a price increase of approximately %s/month (%s/year)\n", c.Format(cb.TotalMonthly), c.Format(cb.TotalMonthly*12))
 		} else if cb.TotalMonthly < 0 {
 			savings := cb.TotalMonthly * -1
-			fmt.Printf("The changes will yield a savings of approximately $%.2f/month ($%.2f/year)\n", savings, savings*12)
+			fmt.Printf("The changes will yield a savings of approximately %s/month (%s/year)\n", c.Format(savings), c.Format(savings*12))
+		}
+	}
+
+	if calibrated := CURCalibratedCount(recommendations); calibrated > 0 {
+		fmt.Printf("Costs calibrated against the Cost and Usage Report for %d of %d recommendations\n", calibrated, len(recommendations))
+	}
+
+	// Effective figure after reserved instance coverage
+	if cb.HasEffective {
+		if effectiveLine := formatLine("With reserved instance coverage", cb.EffectiveMonthly); effectiveLine != "" {
+			fmt.Println(effectiveLine)
+		} else {
+			fmt.Println("With reserved instance coverage: no cost impact")
+		}
+		for _, warning := range ReservedInstanceWarnings(recommendations) {
+			fmt.Printf("  Warning: %s\n", warning)
+		}
+	}
+
+	if upgrades := EngineUpgrades(recommendations); len(upgrades) > 0 {
+		fmt.Println("Engine upgrades that unlock savings:")
+		for _, u := range upgrades {
+			fmt.Printf("  %s %s -> %s: %d instance(s), additional savings of approximately %s/month (%s/year)\n",
+				u.Engine, u.EngineVersion, u.RequiredEngineVersion, u.Instances, c.Format(u.MonthlySavings), c.Format(u.MonthlySavings*12))
+		}
+	}
+
+	writeBreakdown := func(label string, cb CostBreakdown) {
+		if cb.TotalMonthly > 0 {
+			fmt.Printf("  %s: price increase of approximately %s/month (%s/year)\n", label, c.Format(cb.TotalMonthly), c.Format(cb.TotalMonthly*12))
+		} else if cb.TotalMonthly < 0 {
+			savings := cb.TotalMonthly * -1
+			fmt.Printf("  %s: savings of approximately %s/month (%s/year)\n", label, c.Format(savings), c.Format(savings*12))
+		} else {
+			fmt.Printf("  %s: no cost impact\n", label)
 		}
 	}
 
@@ -1020,15 +1628,15 @@ func writeApproximateCostDifference(recommendations []types.Recommendation) {
 	regionalCB, regions := CalculateRegionalCostBreakdown(recommendations)
 	if len(regions) > 1 {
 		for _, region := range regions {
-			rcb := regionalCB[region]
-			if rcb.TotalMonthly > 0 {
-				fmt.Printf("  %s: price increase of approximately $%.2f/month ($%.2f/year)\n", region, rcb.TotalMonthly, rcb.TotalMonthly*12)
-			} else if rcb.TotalMonthly < 0 {
-				savings := rcb.TotalMonthly * -1
-				fmt.Printf("  %s: savings of approximately $%.2f/month ($%.2f/year)\n", region, savings, savings*12)
-			} else {
-				fmt.Printf("  %s: no cost impact\n", region)
-			}
+			writeBreakdown(region, regionalCB[region])
+		}
+	}
+
+	// Per-account breakdown in multi-account mode
+	accountCB, accounts := CalculateAccountCostBreakdown(recommendations)
+	if len(accounts) > 0 {
+		for _, account := range accounts {
+			writeBreakdown("Account "+account, accountCB[account])
 		}
 	}
 }
@@ -1096,8 +1704,8 @@ func (r *RDSRightSize) getMemoryUtilization(metrics *cwTypes.Metrics, instancePr
 	return &returnValue, nil
 }
 
-func (r *RDSRightSize) getMetrics(ctx context.Context, instance *rdsTypes.Instance) (*cwTypes.Metrics, error) {
-	return r.cloudWatch.GetMetrics(ctx, instance.DBInstanceIdentifier, r.period, r.statistic)
+func (r *RDSRightSize) getMetrics(ctx context.Context, instance *rdsTypes.Instance, since time.Time) (*cwTypes.Metrics, error) {
+	return r.cloudWatch.GetMetrics(ctx, instance.DBInstanceIdentifier, r.period, r.statistic, since)
 }
 
 func (r *RDSRightSize) getBandwidthUtilization(metrics *cwTypes.Metrics, instanceProperties *types.InstanceProperties) (*types.BandwidthUtilization, error) {
@@ -1178,6 +1786,54 @@ func (r *RDSRightSize) getCPUUtilization(metrics *cwTypes.Metrics) (*types.CPUUt
 	return &returnValue, nil
 }
 
+// measuredMetrics summarizes an instance's CloudWatch statistics, relating them to
+// the capacity of its current class when that class is in the instance types. The
+// current max_connections is the parameter group's static value, or the class default.
+func (r *RDSRightSize) measuredMetrics(ctx context.Context, metrics *cwTypes.Metrics, instance *rdsTypes.Instance) *types.RecommendationMetrics {
+	value := func(name cwTypes.RdsMetricName) *float64 {
+		if metric, ok := metrics.InstanceMetrics[name]; ok {
+			return metric.Value
+		}
+		return nil
+	}
+
+	measured := &types.RecommendationMetrics{
+		Statistic:       r.statistic,
+		DataPoints:      metrics.DataPoints,
+		WindowDays:      math.Round(time.Since(metrics.Since).Hours()/24*10) / 10,
+		CPU:             value(cwTypes.CPUUtilization),
+		ReadThroughput:  value(cwTypes.ReadThroughput),
+		WriteThroughput: value(cwTypes.WriteThroughput),
+		PeakConnections: value(cwTypes.DatabaseConnections),
+	}
+	if freeable := value(cwTypes.FreeableMemory); freeable != nil {
+		measured.FreeableMemoryGiB = Float64(*freeable / (1 << 30))
+	}
+
+	props, ok := r.lookupInstanceProperties(*instance.DBInstanceClass, instance.Engine)
+	if !ok {
+		return measured
+	}
+	if measured.FreeableMemoryGiB != nil && props.Mem > 0 {
+		measured.FreeableMemoryPercent = Float64(*measured.FreeableMemoryGiB * 100.0 / float64(props.Mem))
+	}
+	if measured.ReadThroughput != nil && measured.WriteThroughput != nil && props.MaxBandwidth != nil && *props.MaxBandwidth > 0 {
+		total := *measured.ReadThroughput + *measured.WriteThroughput
+		measured.BandwidthPercent = Float64(total / float64(*props.MaxBandwidth*mbit_bytes) * 100.0)
+	}
+	if measured.PeakConnections != nil {
+		maxConns := r.configuredMaxConnections(ctx, instance)
+		if maxConns == nil {
+			maxConns = props.MaxConnections
+		}
+		if maxConns != nil && *maxConns > 0 {
+			measured.MaxConnections = maxConns
+			measured.ConnectionsPercent = Float64(*measured.PeakConnections * 100.0 / float64(*maxConns))
+		}
+	}
+	return measured
+}
+
 // getPeakConnections extracts the peak (Maximum) database connections value from metrics.
 func (r *RDSRightSize) getPeakConnections(metrics *cwTypes.Metrics) *float64 {
 	metric, ok := metrics.InstanceMetrics[cwTypes.DatabaseConnections]
@@ -1192,94 +1848,167 @@ func (r *RDSRightSize) getPeakConnections(metrics *cwTypes.Metrics) *float64 {
 func (r *RDSRightSize) getEffectiveMaxConnections(ctx context.Context, instance *rdsTypes.Instance, targetProperties *types.InstanceProperties) *int64 {
 	targetMax := targetProperties.MaxConnections
 
-	// Try to get the current instance's configured max_connections from its parameter group
-	if instance.DBParameterGroupName != nil && *instance.DBParameterGroupName != "" {
-		pgName := *instance.DBParameterGroupName
-
-		// Check cache first
-		if cached, found := r.maxConnCache[pgName]; found {
-			if cached != nil && targetMax != nil && *cached < *targetMax {
-				return cached
-			}
-			return targetMax
-		}
-
-		// Query the API
-		apiMax, err := r.rds.GetMaxConnections(ctx, instance.DBParameterGroupName)
-		r.maxConnCache[pgName] = apiMax // cache even nil results
-
-		if err == nil && apiMax != nil {
-			// If the user has set a static max_connections lower than the target's default,
-			// use the user's value (it will remain the same after resize if same param group)
-			if targetMax != nil && *apiMax < *targetMax {
-				return apiMax
-			}
-		}
+	// If the user has set a static max_connections lower than the target's default,
+	// use the user's value (it will remain the same after resize if same param group)
+	if configured := r.configuredMaxConnections(ctx, instance); configured != nil && targetMax != nil && *configured < *targetMax {
+		return configured
 	}
 
 	return targetMax
 }
 
-func loadInstanceTypes(source *string) types.InstanceTypes {
-	if strings.HasPrefix(*source, "http://") || strings.HasPrefix(*source, "https://") {
-		return loadInstanceTypesFromURL(*source)
+// configuredMaxConnections returns the static max_connections set in the instance's
+// parameter group, or nil when it is unset, a formula or cannot be read. Results
+// are cached per parameter group.
+func (r *RDSRightSize) configuredMaxConnections(ctx context.Context, instance *rdsTypes.Instance) *int64 {
+	if instance.DBParameterGroupName == nil || *instance.DBParameterGroupName == "" {
+		return nil
+	}
+	pgName := *instance.DBParameterGroupName
+
+	if cached, found := r.maxConnCache[pgName]; found {
+		return cached
 	}
 
-	// Treat as local file path (strip optional file:// prefix)
-	path := strings.TrimPrefix(*source, "file://")
-	return loadInstanceTypesFromFile(path)
+	apiMax, err := r.rds.GetMaxConnections(ctx, instance.DBParameterGroupName)
+	if err != nil {
+		apiMax = nil
+	}
+	r.maxConnCache[pgName] = apiMax // cache even nil results
+	return apiMax
 }
 
-func loadInstanceTypesFromFile(path string) types.InstanceTypes {
-	body, err := os.ReadFile(path)
+// LoadInstanceTypes reads the instance types JSON from a URL or local path (an optional
+// file:// prefix is stripped), validates it, and applies pricing overrides. Errors
+// that do not prevent analysis are returned in the file's Warnings. URLs are
+// read through the download cache c, which may be nil. The result
+// is meant to be loaded once per run and shared read-only across analyzers.
+func LoadInstanceTypes(source string, pricingOverrides *types.PricingOverrides, c *cache.Cache) (*types.InstanceTypesFile, error) {
+	file, err := ReadInstanceTypes(source, c)
 	if err != nil {
-		log.Fatalf("Failed to read instance types file %s: %v", path, err)
+		return nil, err
 	}
 
-	instanceTypes := types.InstanceTypes{}
-	jsonErr := json.Unmarshal(body, &instanceTypes)
-	if jsonErr != nil {
-		log.Fatalf("Failed to parse instance types JSON from %s: %v", path, jsonErr)
+	warnings, err := validateInstanceTypes(file.InstanceTypes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid instance types in %s: %w", source, err)
 	}
 
-	return instanceTypes
+	instanceTypes, err := applyPricingOverrides(file.InstanceTypes, pricingOverrides)
+	if err != nil {
+		return nil, err
+	}
+	loaded := *file
+	loaded.InstanceTypes = instanceTypes
+	loaded.Warnings = warnings
+	if len(file.StoragePricing) > 0 {
+		loaded.StoragePricing = applyStorageOverrides(file.StoragePricing, pricingOverrides)
+	}
+	return &loaded, nil
 }
 
-func loadInstanceTypesFromURL(url string) types.InstanceTypes {
-	httpClient := http.Client{
-		Timeout: time.Second * 10,
+// ReadInstanceTypes reads and parses the instance types JSON from a URL or local
+// path like LoadInstanceTypes, without validating it or applying overrides. Both
+// the versioned format and the legacy bare map of classes (read as schema
+// version 1, without metadata) are accepted.
+func ReadInstanceTypes(source string, c *cache.Cache) (*types.InstanceTypesFile, error) {
+	var (
+		body []byte
+		err  error
+	)
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		body, err = download(c, source)
+		if err != nil {
+			err = fmt.Errorf("failed to load instance types: %w", err)
+		}
+	} else {
+		path := strings.TrimPrefix(source, "file://")
+		body, err = os.ReadFile(path)
+		if err != nil {
+			err = fmt.Errorf("failed to read instance types file %s: %w", path, err)
+		}
 	}
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	res, getErr := httpClient.Do(req)
-
-	if getErr != nil {
-		log.Fatal(getErr)
+	// Class keys never collide with "schemaVersion", so its presence tells the
+	// envelope apart from the legacy bare map
+	var probe struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse instance types JSON from %s: %w", source, err)
 	}
 
-	if res.Body != nil {
-		defer func(Body io.ReadCloser) {
-			_ = Body.Close()
-		}(res.Body)
+	file := &types.InstanceTypesFile{SchemaVersion: 1}
+	switch {
+	case probe.SchemaVersion == 0:
+		file.InstanceTypes = types.InstanceTypes{}
+		if err := json.Unmarshal(body, &file.InstanceTypes); err != nil {
+			return nil, fmt.Errorf("failed to parse instance types JSON from %s: %w", source, err)
+		}
+	case probe.SchemaVersion > types.InstanceTypesSchemaVersion:
+		return nil, fmt.Errorf("instance types in %s use schema version %d, newer than the supported %d; upgrade rds-right-size", source, probe.SchemaVersion, types.InstanceTypesSchemaVersion)
+	default:
+		if err := json.Unmarshal(body, file); err != nil {
+			return nil, fmt.Errorf("failed to parse instance types JSON from %s: %w", source, err)
+		}
 	}
+	return file, nil
+}
 
-	body, readErr := io.ReadAll(res.Body)
+// validateInstanceTypes rejects instance types with errors in loadBlockingChecks
+// and returns the other errors as warnings. SeverityWarning issues are left to the
+// validate-types subcommand.
+func validateInstanceTypes(instanceTypes types.InstanceTypes) ([]string, error) {
+	var blocking, nonBlocking []ValidationIssue
+	for _, issue := range ValidationErrors(ValidateInstanceTypes(instanceTypes)) {
+		if loadBlockingChecks[issue.Check] {
+			blocking = append(blocking, issue)
+		} else {
+			nonBlocking = append(nonBlocking, issue)
+		}
+	}
+	if len(blocking) > 0 {
+		return nil, errors.New(strings.Join(listIssues(blocking), "; "))
+	}
+	return listIssues(nonBlocking), nil
+}
 
-	if readErr != nil {
-		log.Fatal(readErr)
+// listIssues describes the first few issues, with a note on how many more there are.
+func listIssues(issues []ValidationIssue) []string {
+	const maxListed = 5
+	var lines []string
+	for i, issue := range issues {
+		if i == maxListed {
+			lines = append(lines, fmt.Sprintf("... and %d more (run validate-types for the full list)", len(issues)-maxListed))
+			break
+		}
+		lines = append(lines, issue.String())
 	}
+	return lines
+}
 
-	instanceTypes := types.InstanceTypes{}
-	jsonErr := json.Unmarshal(body, &instanceTypes)
+// downloadTimeout bounds each download of instance types and pricing files.
+const downloadTimeout = 10 * time.Second
 
-	if jsonErr != nil {
-		log.Fatal(jsonErr)
+// download reads url through the download cache c (nil downloads directly).
+func download(c *cache.Cache, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+
+	body, err := c.Open(ctx, url)
+	if err != nil {
+		return nil, err
 	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(body)
 
-	return instanceTypes
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
 }
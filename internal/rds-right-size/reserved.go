@@ -0,0 +1,267 @@
+package rds_right_size
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
+)
+
+// reservedPoolKey identifies a pool of size-flexible reservations. A reservation
+// applies to any instance of the same engine and instance family in the region,
+// so coverage is tracked per (engine, family) in normalized units.
+type reservedPoolKey struct {
+	engine string // normalized engine, e.g. "aurora-mysql"
+	family string // instance family, e.g. "db.r6g"
+}
+
+// reservedPool accumulates reserved and used normalized units for one pool.
+type reservedPool struct {
+	reservedUnits float64
+	usedUnits     float64
+	deltaUnits    float64   // net usage change if all recommendations are applied
+	expiry        time.Time // earliest expiry among the pool's reservations
+}
+
+// sizeNormalizationFactors maps instance sizes to RDS normalized units.
+// Sizes of the form "{n}xlarge" are handled by normalizedUnits.
+var sizeNormalizationFactors = map[string]float64{
+	"micro":  0.5,
+	"small":  1,
+	"medium": 2,
+	"large":  4,
+	"xlarge": 8,
+}
+
+// normalizedUnits returns the RDS size-flexibility normalized units for an
+// instance class (e.g. db.r6g.large -> 4, db.r6g.2xlarge -> 16), or 0 if the
+// size is not recognized.
+func normalizedUnits(dbInstanceClass string) float64 {
+	parts := strings.Split(stripEnginePrefix(dbInstanceClass), ".")
+	if len(parts) < 3 {
+		return 0
+	}
+	size := parts[2]
+	if f, ok := sizeNormalizationFactors[size]; ok {
+		return f
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(size, "xlarge")); err == nil && strings.HasSuffix(size, "xlarge") {
+		return float64(n) * 8
+	}
+	return 0
+}
+
+// reservedEngine normalizes engine names and reservation product descriptions
+// so they can be compared. Legacy "aurora" (MySQL 5.6 compatible) maps to aurora-mysql.
+func reservedEngine(engine string) string {
+	engine = strings.ToLower(strings.TrimSpace(engine))
+	if engine == "aurora" {
+		return "aurora-mysql"
+	}
+	return engine
+}
+
+// newReservedPoolKey builds the pool key for an engine and instance class.
+func newReservedPoolKey(engine *string, dbInstanceClass string) (reservedPoolKey, bool) {
+	if engine == nil {
+		return reservedPoolKey{}, false
+	}
+	family := instanceFamilyName(dbInstanceClass)
+	if family == "" {
+		return reservedPoolKey{}, false
+	}
+	return reservedPoolKey{engine: reservedEngine(*engine), family: family}, true
+}
+
+// instanceFamilyName returns the family part of an instance class,
+// e.g. "db.r6g.xlarge" -> "db.r6g".
+func instanceFamilyName(dbInstanceClass string) string {
+	parts := strings.Split(stripEnginePrefix(dbInstanceClass), ".")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// uncoveredUnits returns how many of used units are billed at on-demand rates.
+func (p *reservedPool) uncoveredUnits(used float64) float64 {
+	return math.Max(0, used-p.reservedUnits)
+}
+
+// marginalOnDemandRatio returns the fraction of a usage change in this pool that
+// is billed at on-demand rates once all recommendations are applied. A ratio of 0
+// means the change is fully absorbed by reservations (no effective savings),
+// 1 means it is fully on-demand.
+func (p *reservedPool) marginalOnDemandRatio() float64 {
+	if p.deltaUnits == 0 {
+		if p.usedUnits > p.reservedUnits {
+			return 1
+		}
+		return 0
+	}
+	before := p.uncoveredUnits(p.usedUnits)
+	after := p.uncoveredUnits(p.usedUnits + p.deltaUnits)
+	return (after - before) / p.deltaUnits
+}
+
+// applyReservedCoverage computes the effective monthly price difference of each
+// recommendation given the active reservations in the region. Usage is measured
+// across all instances (reservations apply regardless of tag filters), and each
+// recommendation's on-demand difference is scaled by the share of the resulting
+// usage change that falls outside reserved capacity. Recommendations that would
+// leave reserved capacity unused get a stranded-reservation warning.
+func (r *RDSRightSize) applyReservedCoverage(recommendations []types.Recommendation, instances []rdsTypes.Instance, reservations []rdsTypes.ReservedInstance) {
+	pools := make(map[reservedPoolKey]*reservedPool)
+
+	for _, res := range reservations {
+		if res.DBInstanceClass == nil || res.DBInstanceCount <= 0 {
+			continue
+		}
+		key, ok := newReservedPoolKey(res.ProductDescription, *res.DBInstanceClass)
+		if !ok {
+			continue
+		}
+		pool, exists := pools[key]
+		if !exists {
+			pool = &reservedPool{}
+			pools[key] = pool
+		}
+		pool.reservedUnits += normalizedUnits(*res.DBInstanceClass) * float64(res.DBInstanceCount)
+		if end := res.EndTime(); !end.IsZero() && (pool.expiry.IsZero() || end.Before(pool.expiry)) {
+			pool.expiry = end
+		}
+	}
+
+	if len(pools) == 0 {
+		return
+	}
+
+	// Usage is counted on the class recommendations are made for, the pending class
+	// of an instance scheduled to change class, so it matches the deltas below
+	for _, instance := range instances {
+		if instance.DBInstanceClass == nil {
+			continue
+		}
+		class := r.analyzedClass(&instance)
+		if key, ok := newReservedPoolKey(instance.Engine, class); ok {
+			if pool, exists := pools[key]; exists {
+				pool.usedUnits += normalizedUnits(class)
+			}
+		}
+	}
+
+	// Net usage change per pool if every recommendation is applied. Instances
+	// reported without a change (Optimized, Skipped, Unavailable) keep their usage.
+	for _, rec := range recommendations {
+		if rec.DBInstanceClass == nil || !changesClass(rec) {
+			continue
+		}
+		if key, ok := newReservedPoolKey(rec.Engine, *rec.DBInstanceClass); ok {
+			if pool, exists := pools[key]; exists {
+				pool.deltaUnits -= normalizedUnits(*rec.DBInstanceClass)
+			}
+		}
+		if !rec.Recommendation.RemovesInstance() && rec.RecommendedInstanceType != nil {
+			if key, ok := newReservedPoolKey(rec.Engine, *rec.RecommendedInstanceType); ok {
+				if pool, exists := pools[key]; exists {
+					pool.deltaUnits += normalizedUnits(*rec.RecommendedInstanceType)
+				}
+			}
+		}
+	}
+
+	ratioFor := func(engine *string, dbInstanceClass string) (float64, *reservedPool) {
+		key, ok := newReservedPoolKey(engine, dbInstanceClass)
+		if !ok {
+			return 1, nil
+		}
+		pool, exists := pools[key]
+		if !exists {
+			return 1, nil
+		}
+		return pool.marginalOnDemandRatio(), pool
+	}
+
+	for i := range recommendations {
+		rec := &recommendations[i]
+		if rec.DBInstanceClass == nil || rec.CurrentInstanceProperties == nil || !changesClass(*rec) {
+			continue
+		}
+
+		currentRatio, currentPool := ratioFor(rec.Engine, *rec.DBInstanceClass)
+		effective := -r.price(*rec.CurrentInstanceProperties, &rec.Instance) * hours_month * currentRatio
+
+		var targetPool *reservedPool
+		if !rec.Recommendation.RemovesInstance() && rec.RecommendedInstanceType != nil && rec.TargetInstanceProperties != nil {
+			var targetRatio float64
+			targetRatio, targetPool = ratioFor(rec.Engine, *rec.RecommendedInstanceType)
+			effective += r.price(*rec.TargetInstanceProperties, &rec.Instance) * hours_month * targetRatio
+		}
+
+		if currentPool == nil && targetPool == nil {
+			continue
+		}
+
+		rec.EffectiveMonthlyPriceDiff = Float64(effective)
+
+		if currentPool != nil && currentPool.usedUnits > 0 {
+			rec.ReservedCoverage = Float64(math.Min(1, currentPool.reservedUnits/currentPool.usedUnits))
+		}
+
+		// Warn when this change releases capacity the reservations keep paying for
+		released := normalizedUnits(*rec.DBInstanceClass)
+		if targetPool == currentPool && rec.RecommendedInstanceType != nil {
+			released -= normalizedUnits(*rec.RecommendedInstanceType)
+		}
+		if currentPool != nil && released > 0 && currentPool.deltaUnits < 0 {
+			strandedBefore := math.Max(0, currentPool.reservedUnits-currentPool.usedUnits)
+			strandedAfter := math.Max(0, currentPool.reservedUnits-(currentPool.usedUnits+currentPool.deltaUnits))
+			if strandedAfter > strandedBefore {
+				rec.ReservedInstanceWarning = strandedReservationWarning(*rec.DBInstanceClass, currentPool, strandedAfter)
+			}
+		}
+	}
+}
+
+// strandedReservationWarning describes reserved capacity left unused in a pool.
+func strandedReservationWarning(dbInstanceClass string, pool *reservedPool, strandedUnits float64) string {
+	msg := fmt.Sprintf("%.1f of %.1f normalized units of %s reservations would be unused",
+		strandedUnits, pool.reservedUnits, instanceFamilyName(dbInstanceClass))
+	if !pool.expiry.IsZero() {
+		msg += " until " + pool.expiry.Format("2006-01-02")
+	}
+	return msg
+}
+
+// ReservedInstanceWarnings returns the distinct stranded-reservation warnings
+// across recommendations, sorted for stable output.
+func ReservedInstanceWarnings(recommendations []types.Recommendation) []string {
+	seen := make(map[string]bool)
+	var warnings []string
+	for _, rec := range recommendations {
+		if rec.ReservedInstanceWarning == "" {
+			continue
+		}
+		msg := rec.ReservedInstanceWarning
+		if rec.Region != "" {
+			msg = rec.Region + ": " + msg
+		}
+		if !seen[msg] {
+			seen[msg] = true
+			warnings = append(warnings, msg)
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// changesClass reports whether a recommendation removes the instance or moves it
+// to another class.
+func changesClass(rec types.Recommendation) bool {
+	return rec.Recommendation.RemovesInstance() || rec.RecommendedInstanceType != nil
+}
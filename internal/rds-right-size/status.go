@@ -0,0 +1,110 @@
+package rds_right_size
+
+import (
+	"math"
+
+	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
+)
+
+// analyzableStatuses are the DB instance states in which the instance serves its
+// normal workload. Instances in any other state (stopped, modifying, rebooting,
+// ...) are reported as Unavailable instead of analyzed.
+var analyzableStatuses = map[string]bool{
+	"available":                       true,
+	"backing-up":                      true,
+	"storage-optimization":            true,
+	"configuring-enhanced-monitoring": true,
+	"configuring-iam-database-auth":   true,
+	"configuring-log-exports":         true,
+}
+
+// analyzable reports whether an instance's status allows it to be analyzed.
+// Instances without a status are assumed available.
+func analyzable(instance *rdsTypes.Instance) bool {
+	return instance.DBInstanceStatus == nil || analyzableStatuses[*instance.DBInstanceStatus]
+}
+
+// unavailableRecommendation reports an instance that was not analyzed because of
+// its status.
+func unavailableRecommendation(instance rdsTypes.Instance) types.Recommendation {
+	return types.Recommendation{
+		Instance:       instance,
+		Recommendation: types.Unavailable,
+		Reason:         types.InstanceUnavailableReason,
+	}
+}
+
+// applyPendingClass switches an instance scheduled to change class to its pending
+// class, so it is analyzed as it will run. The measured CPU is scaled by the ratio
+// of vCPUs and freeable memory shifted by the difference in memory. It returns the
+// class the instance runs today, or "" when there is no pending class or either
+// class is not in the instance types.
+func (r *RDSRightSize) applyPendingClass(instance *rdsTypes.Instance, metrics *cwTypes.Metrics) string {
+	pending, current, target, ok := r.pendingClass(instance)
+	if !ok {
+		return ""
+	}
+	running := *instance.DBInstanceClass
+
+	if cpu, ok := metrics.InstanceMetrics[cwTypes.CPUUtilization]; ok && cpu.Value != nil {
+		projected := math.Min(*cpu.Value*float64(current.Vcpu)/float64(target.Vcpu), 100)
+		metrics.InstanceMetrics[cwTypes.CPUUtilization] = cwTypes.Metric{Value: &projected}
+	}
+	if freeable, ok := metrics.InstanceMetrics[cwTypes.FreeableMemory]; ok && freeable.Value != nil {
+		projected := math.Max(*freeable.Value+float64(target.Mem-current.Mem)*(1<<30), 0)
+		metrics.InstanceMetrics[cwTypes.FreeableMemory] = cwTypes.Metric{Value: &projected}
+	}
+
+	instance.DBInstanceClass = &pending
+	return running
+}
+
+// pendingClass returns the class an instance is scheduled to change to, with the
+// properties of its running and pending classes. ok is false when there is no
+// pending class or either class is not in the instance types.
+func (r *RDSRightSize) pendingClass(instance *rdsTypes.Instance) (pending string, current, target types.InstanceProperties, ok bool) {
+	if instance.PendingModifiedValues == nil || instance.PendingModifiedValues.DBInstanceClass == nil || instance.DBInstanceClass == nil {
+		return "", current, target, false
+	}
+	running, pending := *instance.DBInstanceClass, *instance.PendingModifiedValues.DBInstanceClass
+	if pending == "" || pending == running {
+		return "", current, target, false
+	}
+
+	current, ok = r.lookupInstanceProperties(running, instance.Engine)
+	if !ok || current.Vcpu <= 0 {
+		return "", current, target, false
+	}
+	target, ok = r.lookupInstanceProperties(pending, instance.Engine)
+	if !ok || target.Vcpu <= 0 {
+		return "", current, target, false
+	}
+	return pending, current, target, true
+}
+
+// analyzedClass returns the class an instance is analyzed against: its pending
+// class when applyPendingClass would switch to it, else the class it runs.
+func (r *RDSRightSize) analyzedClass(instance *rdsTypes.Instance) string {
+	if pending, _, _, ok := r.pendingClass(instance); ok {
+		return pending
+	}
+	return *instance.DBInstanceClass
+}
+
+// applyPendingEngineVersion switches an instance scheduled for an engine upgrade
+// to its pending version, so scaling targets must be orderable with the version
+// it will run. It returns the version the instance runs today, or "" when there is
+// no pending version.
+func applyPendingEngineVersion(instance *rdsTypes.Instance) string {
+	if instance.PendingModifiedValues == nil || instance.PendingModifiedValues.EngineVersion == nil || instance.EngineVersion == nil {
+		return ""
+	}
+	running, pending := *instance.EngineVersion, *instance.PendingModifiedValues.EngineVersion
+	if pending == "" || pending == running {
+		return ""
+	}
+	instance.EngineVersion = &pending
+	return running
+}
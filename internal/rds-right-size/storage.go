@@ -0,0 +1,192 @@
+package rds_right_size
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/luneo7/rds-right-size/internal/cache"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
+)
+
+// storageSwitchMinSavingsRatio is the minimum projected savings, as a fraction of the
+// cluster's current monthly cost, before a storage configuration change is recommended.
+// Aurora only allows switching to I/O-Optimized once every 30 days, so marginal
+// differences are not worth the churn.
+const storageSwitchMinSavingsRatio = 0.05
+
+// bytesPerGiB converts VolumeBytesUsed to GiB for GB-month storage rates.
+const bytesPerGiB = 1 << 30
+
+// analyzeClusterStorage models the monthly cost of every Aurora cluster with at least
+// one analyzed instance under both Standard and I/O-Optimized storage, and returns a
+// StorageConfiguration recommendation for clusters where the other configuration is
+// cheaper. Instance costs cover all cluster members, including those excluded by tags,
+// since the storage configuration applies to the whole cluster.
+func (r *RDSRightSize) analyzeClusterStorage(ctx context.Context, c *cache.Cache, instances []rdsTypes.Instance, analyzed []rdsTypes.Instance, warn func(instanceId, msg string)) ([]types.Recommendation, error) {
+	if r.storagePricing == nil {
+		pricing, err := loadStoragePricing(r.storagePricingSource, c)
+		if err != nil {
+			return nil, err
+		}
+		r.storagePricing = applyStorageOverrides(pricing, r.pricingOverrides)
+	}
+
+	prices, ok := r.storagePricing[r.region]
+	if !ok {
+		return nil, fmt.Errorf("no Aurora storage pricing for region %s in %s", r.region, r.storagePricingSource)
+	}
+
+	members := make(map[string][]rdsTypes.Instance)
+	for _, instance := range instances {
+		if instance.DBClusterIdentifier == nil || *instance.DBClusterIdentifier == "" {
+			continue
+		}
+		if instance.Engine == nil || !strings.HasPrefix(*instance.Engine, "aurora") {
+			continue
+		}
+		members[*instance.DBClusterIdentifier] = append(members[*instance.DBClusterIdentifier], instance)
+	}
+
+	clusterIds := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, instance := range analyzed {
+		if instance.DBClusterIdentifier == nil {
+			continue
+		}
+		clusterId := *instance.DBClusterIdentifier
+		if _, isAurora := members[clusterId]; isAurora && !seen[clusterId] {
+			seen[clusterId] = true
+			clusterIds = append(clusterIds, clusterId)
+		}
+	}
+	sort.Strings(clusterIds)
+
+	recommendations := make([]types.Recommendation, 0)
+	for _, clusterId := range clusterIds {
+		cluster := members[clusterId]
+
+		cost, err := r.clusterStorageCost(ctx, clusterId, cluster, prices)
+		if err != nil {
+			warn(clusterId, err.Error())
+			continue
+		}
+
+		currentStorage := types.AuroraStandardStorage
+		if cluster[0].StorageType != nil {
+			currentStorage = *cluster[0].StorageType
+		}
+
+		current, target := cost.Standard, cost.IOOptimized
+		targetStorage := types.AuroraIOOptimizedStorage
+		reason := types.IOOptimizedCheaperReason
+		if currentStorage == types.AuroraIOOptimizedStorage {
+			current, target = cost.IOOptimized, cost.Standard
+			targetStorage = types.AuroraStandardStorage
+			reason = types.StandardStorageCheaperReason
+		}
+
+		savings := current.Total - target.Total
+		if savings <= 0 || savings < current.Total*storageSwitchMinSavingsRatio {
+			continue
+		}
+
+		clusterIdCopy := clusterId
+		currentStorageCopy := currentStorage
+		recommendations = append(recommendations, types.Recommendation{
+			Instance: rdsTypes.Instance{
+				DBClusterIdentifier: &clusterIdCopy,
+				Engine:              cluster[0].Engine,
+				EngineVersion:       cluster[0].EngineVersion,
+				StorageType:         &currentStorageCopy,
+			},
+			Recommendation:              types.StorageConfiguration,
+			Reason:                      reason,
+			RecommendedStorageType:      &targetStorage,
+			MonthlyApproximatePriceDiff: Float64(target.Total - current.Total),
+			StorageCost:                 cost,
+		})
+	}
+
+	return recommendations, nil
+}
+
+// clusterStorageCost fetches a cluster's volume metrics and models its monthly cost
+// under both storage configurations. Billed I/O over the lookback period is scaled
+// to a month.
+func (r *RDSRightSize) clusterStorageCost(ctx context.Context, clusterId string, cluster []rdsTypes.Instance, prices types.StoragePrices) (*types.StorageCostComparison, error) {
+	var cost types.StorageCostComparison
+
+	for _, instance := range cluster {
+		if instance.DBInstanceClass == nil {
+			continue
+		}
+		props, ok := r.lookupInstanceProperties(*instance.DBInstanceClass, instance.Engine)
+		if !ok {
+			return nil, fmt.Errorf("instance class %s of %s not found in instance types", *instance.DBInstanceClass, *instance.DBInstanceIdentifier)
+		}
+		if _, ok := props.IOOptimizedPricing[r.region]; !ok {
+			return nil, fmt.Errorf("no I/O-Optimized price for %s in %s", *instance.DBInstanceClass, r.region)
+		}
+		cost.Standard.Instances += props.GetInstancePrice(r.region, r.pricingModel, types.AuroraStandardStorage) * hours_month
+		cost.IOOptimized.Instances += props.GetInstancePrice(r.region, r.pricingModel, types.AuroraIOOptimizedStorage) * hours_month
+	}
+
+	metrics, err := r.cloudWatch.GetClusterStorageMetrics(ctx, &clusterId, r.period)
+	if err != nil {
+		return nil, err
+	}
+	if metrics.VolumeBytesUsed == nil {
+		return nil, fmt.Errorf("missing VolumeBytesUsed metric for cluster %s", clusterId)
+	}
+
+	var totalIOs float64
+	if metrics.VolumeReadIOPs != nil {
+		totalIOs += *metrics.VolumeReadIOPs
+	}
+	if metrics.VolumeWriteIOPs != nil {
+		totalIOs += *metrics.VolumeWriteIOPs
+	}
+
+	cost.VolumeGiB = *metrics.VolumeBytesUsed / bytesPerGiB
+	cost.MonthlyIORequests = totalIOs * hours_month / float64(r.period*24)
+
+	cost.Standard.Storage = cost.VolumeGiB * prices.StandardStorage
+	cost.Standard.IO = cost.MonthlyIORequests / 1e6 * prices.StandardIO
+	cost.IOOptimized.Storage = cost.VolumeGiB * prices.IOOptimizedStorage
+
+	cost.Standard.Total = cost.Standard.Instances + cost.Standard.Storage + cost.Standard.IO
+	cost.IOOptimized.Total = cost.IOOptimized.Instances + cost.IOOptimized.Storage + cost.IOOptimized.IO
+
+	return &cost, nil
+}
+
+// loadStoragePricing reads the Aurora storage pricing sidecar file written next to
+// legacy instance types files, from a local path or URL (through the download cache c).
+func loadStoragePricing(source string, c *cache.Cache) (types.StoragePricing, error) {
+	var body []byte
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		var err error
+		body, err = download(c, source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load storage pricing: %w", err)
+		}
+	} else {
+		path := strings.TrimPrefix(source, "file://")
+		var err error
+		body, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read storage pricing file %s (regenerate instance types with generate-types): %w", path, err)
+		}
+	}
+
+	pricing := types.StoragePricing{}
+	if err := json.Unmarshal(body, &pricing); err != nil {
+		return nil, fmt.Errorf("failed to parse storage pricing JSON from %s: %w", source, err)
+	}
+	return pricing, nil
+}
@@ -0,0 +1,102 @@
+package rds_right_size
+
+import (
+	"fmt"
+
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+)
+
+// Names of the checks recorded in decision traces.
+const (
+	checkInstanceTypes = "instance-types"
+	checkRegion        = "region"
+	checkEngineVersion = "engine-version"
+	checkBandwidth     = "bandwidth"
+	checkProjectedCPU  = "projected-cpu"
+	checkCapacity      = "capacity"
+)
+
+// decisionTrace records the candidate classes considered for a recommendation.
+// Methods on a nil *decisionTrace do nothing, so tracing is free unless
+// AnalysisOptions.Explain is set.
+type decisionTrace struct {
+	steps []types.DecisionStep
+}
+
+// newTrace returns a trace when the analysis explains its decisions, nil otherwise.
+func (r *RDSRightSize) newTrace() *decisionTrace {
+	if !r.explain {
+		return nil
+	}
+	return &decisionTrace{}
+}
+
+// Steps returns the recorded steps.
+func (t *decisionTrace) Steps() []types.DecisionStep {
+	if t == nil {
+		return nil
+	}
+	return t.steps
+}
+
+// traceStep is a candidate being checked; it is added to its trace by accept or reject.
+type traceStep struct {
+	trace *decisionTrace
+	step  types.DecisionStep
+}
+
+// candidate starts a step for a candidate class considered in stage.
+func (t *decisionTrace) candidate(stage types.DecisionStage, class string) *traceStep {
+	if t == nil {
+		return nil
+	}
+	return &traceStep{trace: t, step: types.DecisionStep{Stage: stage, Candidate: stripEnginePrefix(class)}}
+}
+
+// check records a check and returns passed, so it can be used in conditions.
+func (s *traceStep) check(name string, passed bool, value string) bool {
+	if s != nil {
+		s.step.Checks = append(s.step.Checks, types.DecisionCheck{Name: name, Value: value, Passed: passed})
+	}
+	return passed
+}
+
+func (s *traceStep) accept(reason string) { s.finish(true, reason) }
+func (s *traceStep) reject(reason string) { s.finish(false, reason) }
+
+func (s *traceStep) finish(accepted bool, reason string) {
+	if s == nil {
+		return
+	}
+	s.step.Accepted = accepted
+	s.step.Reason = reason
+	s.trace.steps = append(s.trace.steps, s.step)
+}
+
+// checkOrderable is orderable, recording the region and engine version checks on s.
+func (r *RDSRightSize) checkOrderable(s *traceStep, props types.InstanceProperties, engineVersion string) bool {
+	if r.region != "" && !s.check(checkRegion, props.AvailableInRegion(r.region), r.region) {
+		return false
+	}
+	supported := r.supportsEngineVersion(props, engineVersion)
+	value := engineVersion
+	if !supported {
+		if required := props.RequiredEngineVersion(r.region, engineVersion); required != "" {
+			value += ", requires " + required
+		}
+	}
+	return s.check(checkEngineVersion, supported, value)
+}
+
+// bandwidthValue describes the measured throughput against a class's maximum bandwidth.
+func bandwidthValue(total float64, maxBandwidth *int64) string {
+	if maxBandwidth == nil {
+		return fmt.Sprintf("%.1f MiB/s, maximum unknown", total/(1<<20))
+	}
+	return fmt.Sprintf("%.1f MiB/s of %.1f MiB/s", total/(1<<20), float64(*maxBandwidth*mbit_bytes)/(1<<20))
+}
+
+// capacityValue describes a class's capacity against a required minimum.
+func capacityValue(props, minimum types.InstanceProperties) string {
+	return fmt.Sprintf("%d vCPU, %d GiB (needs %d vCPU, %d GiB)", props.Vcpu, props.Mem, minimum.Vcpu, minimum.Mem)
+}
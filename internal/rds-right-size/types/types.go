@@ -1,14 +1,21 @@
 package types
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/luneo7/rds-right-size/internal/currency"
 	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
 	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
+	"github.com/luneo7/rds-right-size/internal/util"
 )
 
 type CPUUtilizationStatus string
 type BandwidthUtilizationStatus string
 type RecommendationType string
 type RecommendationReason string
+type PricingModel string
 
 // Enum values for CPU Utilization Status
 const (
@@ -21,13 +28,75 @@ const (
 	UpScale                      RecommendationType         = "UpScale"
 	DownScale                    RecommendationType         = "DownScale"
 	Terminate                    RecommendationType         = "Terminate"
+	StorageConfiguration         RecommendationType         = "StorageConfiguration"
+	Optimized                    RecommendationType         = "Optimized"
+	Skipped                      RecommendationType         = "Skipped"
+	Unavailable                  RecommendationType         = "Unavailable"
+	LikelyIdle                   RecommendationType         = "LikelyIdle"
 	NoUsageWithinPeriodReason    RecommendationReason       = "No usage within period"
 	MemoryUnderProvisionedReason RecommendationReason       = "Memory is under provisioned"
 	CPUUnderProvisionedReason    RecommendationReason       = "CPU is under provisioned"
 	CPUOverProvisionedReason     RecommendationReason       = "CPU is over provisioned"
 	ClusterEqualizationReason    RecommendationReason       = "Cluster equalization"
+	IOOptimizedCheaperReason     RecommendationReason       = "I/O-Optimized storage is cheaper"
+	StandardStorageCheaperReason RecommendationReason       = "Standard storage is cheaper"
+	PreviousGenerationReason     RecommendationReason       = "Previous-generation instance class"
+	WithinThresholdsReason       RecommendationReason       = "Utilization is within thresholds"
+	NoScalingTargetReason        RecommendationReason       = "No suitable instance class to scale to"
+	AnalysisSkippedReason        RecommendationReason       = "Instance could not be analyzed"
+	InstanceUnavailableReason    RecommendationReason       = "Instance is not available"
+	MonitoringOnlyReason         RecommendationReason       = "Only monitoring activity within period"
+)
+
+// Aurora cluster storage types.
+const (
+	AuroraStandardStorage    = "aurora"
+	AuroraIOOptimizedStorage = "aurora-iopt1"
+)
+
+// StorageTypeLabel returns a display name for an Aurora cluster storage type.
+func StorageTypeLabel(storageType string) string {
+	if storageType == AuroraIOOptimizedStorage {
+		return "I/O-Optimized"
+	}
+	return "Standard"
+}
+
+// Enum values for Pricing Model
+const (
+	OnDemand           PricingModel = "on-demand"
+	RI1yNoUpfront      PricingModel = "ri-1y-no-upfront"
+	RI1yPartialUpfront PricingModel = "ri-1y-partial-upfront"
+	RI1yAllUpfront     PricingModel = "ri-1y-all-upfront"
+	RI3yNoUpfront      PricingModel = "ri-3y-no-upfront"
+	RI3yPartialUpfront PricingModel = "ri-3y-partial-upfront"
+	RI3yAllUpfront     PricingModel = "ri-3y-all-upfront"
 )
 
+// PricingModels lists all supported pricing models, on-demand first.
+var PricingModels = []PricingModel{
+	OnDemand,
+	RI1yNoUpfront,
+	RI1yPartialUpfront,
+	RI1yAllUpfront,
+	RI3yNoUpfront,
+	RI3yPartialUpfront,
+	RI3yAllUpfront,
+}
+
+// ParsePricingModel validates a pricing model name. An empty string means on-demand.
+func ParsePricingModel(s string) (PricingModel, error) {
+	if s == "" {
+		return OnDemand, nil
+	}
+	for _, m := range PricingModels {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown pricing model %q", s)
+}
+
 type CPUUtilization struct {
 	Value  *float64
 	Status CPUUtilizationStatus
@@ -44,6 +113,39 @@ type MemoryUtilization struct {
 	UnderProvisioned *bool
 }
 
+// ClassChangeSource tells how an instance class change was found.
+type ClassChangeSource string
+
+const (
+	// ClassChangeEvent is a change reported by the RDS events
+	ClassChangeEvent ClassChangeSource = "RDSEvent"
+	// ClassChangeFreeableMemory is a change detected from a step in the daily
+	// maximum FreeableMemory, for changes older than the RDS event retention
+	ClassChangeFreeableMemory ClassChangeSource = "FreeableMemory"
+)
+
+// RecommendationMetrics holds the utilization measured for an instance over the
+// lookback period, or the part of it since the last instance class change
+// (ClassChangedAt). Values use Statistic, except PeakConnections (the maximum);
+// throughput is in bytes/second, and percentages are of the current instance
+// class's capacity (omitted when the class is not in the instance types).
+type RecommendationMetrics struct {
+	Statistic             cwTypes.StatName  `json:"Statistic"`
+	DataPoints            int               `json:"DataPoints"`
+	CPU                   *float64          `json:"CPU,omitempty"`
+	FreeableMemoryGiB     *float64          `json:"FreeableMemoryGiB,omitempty"`
+	FreeableMemoryPercent *float64          `json:"FreeableMemoryPercent,omitempty"`
+	ReadThroughput        *float64          `json:"ReadThroughput,omitempty"`
+	WriteThroughput       *float64          `json:"WriteThroughput,omitempty"`
+	BandwidthPercent      *float64          `json:"BandwidthPercent,omitempty"`
+	PeakConnections       *float64          `json:"PeakConnections,omitempty"`
+	MaxConnections        *int64            `json:"MaxConnections,omitempty"`
+	ConnectionsPercent    *float64          `json:"ConnectionsPercent,omitempty"`
+	WindowDays            float64           `json:"WindowDays"`
+	ClassChangedAt        *time.Time        `json:"ClassChangedAt,omitempty"`
+	ClassChangeSource     ClassChangeSource `json:"ClassChangeSource,omitempty"`
+}
+
 type InstanceTypes map[string]InstanceProperties
 
 type InstanceProperties struct {
@@ -56,6 +158,20 @@ type InstanceProperties struct {
 	Pricing          map[string]float64 `json:"pricing,omitempty"`
 	MinEngineVersion string             `json:"minEngineVersion,omitempty"`
 	StdPrice         float64            `json:"stdPrice,omitempty"`
+	// ReservedPricing holds effective hourly reserved rates (upfront fee amortized
+	// over the term plus the hourly charge) per region and pricing model.
+	ReservedPricing map[string]map[PricingModel]float64 `json:"reservedPricing,omitempty"`
+	// IOOptimizedPricing holds on-demand hourly rates per region for instances in
+	// Aurora I/O-Optimized (aurora-iopt1) clusters.
+	IOOptimizedPricing map[string]float64 `json:"ioOptimizedPricing,omitempty"`
+	// EngineVersions holds the engine major/minor versions (util.EngineMinorVersion)
+	// the class is orderable with, per region.
+	EngineVersions map[string][]string `json:"engineVersions,omitempty"`
+	// PreviousGeneration marks classes AWS no longer lists as current generation or
+	// is retiring; analysis always recommends moving off them.
+	PreviousGeneration bool `json:"previousGeneration,omitempty"`
+	// EndOfLife is the announced end-of-support date (YYYY-MM-DD) of the class.
+	EndOfLife string `json:"endOfLife,omitempty"`
 }
 
 // GetPrice returns the on-demand hourly price for the given region.
@@ -70,6 +186,180 @@ func (p InstanceProperties) GetPrice(region string) float64 {
 	return p.StdPrice
 }
 
+// GetPriceFor returns the effective hourly price for the given region and pricing model.
+// Reserved models fall back to the on-demand price when no reserved rate is known
+// for the region (e.g., old JSON files or classes without reserved offerings).
+func (p InstanceProperties) GetPriceFor(region string, model PricingModel) float64 {
+	if model != "" && model != OnDemand {
+		if rates, ok := p.ReservedPricing[region]; ok {
+			if price, ok := rates[model]; ok {
+				return price
+			}
+		}
+	}
+	return p.GetPrice(region)
+}
+
+// HasReservedPrice returns true if a reserved rate is known for the region and
+// model, so GetPriceFor does not fall back to the on-demand price. It is always true
+// for the on-demand model.
+func (p InstanceProperties) HasReservedPrice(region string, model PricingModel) bool {
+	if model == "" || model == OnDemand {
+		return true
+	}
+	_, ok := p.ReservedPricing[region][model]
+	return ok
+}
+
+// GetInstancePrice returns the hourly price for the given region and pricing model,
+// using the I/O-Optimized rate when the cluster storage type is aurora-iopt1.
+// Reserved I/O-Optimized rates are derived by applying the on-demand I/O-Optimized
+// premium to the standard reserved rate. Falls back to the standard rate when no
+// I/O-Optimized price is known for the region.
+func (p InstanceProperties) GetInstancePrice(region string, model PricingModel, storageType string) float64 {
+	price := p.GetPriceFor(region, model)
+	if storageType != AuroraIOOptimizedStorage {
+		return price
+	}
+	ioPrice, ok := p.IOOptimizedPricing[region]
+	if !ok || ioPrice <= 0 {
+		return price
+	}
+	if model == "" || model == OnDemand {
+		return ioPrice
+	}
+	if std := p.GetPrice(region); std > 0 {
+		return price * ioPrice / std
+	}
+	return price
+}
+
+// PricingOverrides describes privately negotiated pricing layered on top of the
+// public prices in an instance types file. Multipliers and the global discount
+// scale every hourly rate of a class (on-demand, reserved and I/O-Optimized);
+// absolute prices replace the on-demand rate for a class in a region, and the
+// class's other rates in that region are scaled by the same factor.
+type PricingOverrides struct {
+	// Currency of the absolute Prices. Defaults to USD; any other currency
+	// requires ExchangeRate.
+	Currency string `json:"currency,omitempty"`
+	// ExchangeRate is the number of Currency units per USD, used to convert
+	// absolute Prices to USD.
+	ExchangeRate float64 `json:"exchangeRate,omitempty"`
+	// DiscountPercent is a global discount applied to all instance and storage
+	// prices, including absolute Prices (e.g. 12 for an EDP of 12%).
+	DiscountPercent float64 `json:"discountPercent,omitempty"`
+	// FamilyMultipliers scale prices by instance family (e.g. "db.r6g": 0.9).
+	FamilyMultipliers map[string]float64 `json:"familyMultipliers,omitempty"`
+	// ClassMultipliers scale prices by instance class (e.g. "db.r6g.large": 0.85)
+	// and take precedence over FamilyMultipliers.
+	ClassMultipliers map[string]float64 `json:"classMultipliers,omitempty"`
+	// Prices holds absolute on-demand hourly prices per region and instance class,
+	// in Currency. They take precedence over both multipliers.
+	Prices map[string]map[string]float64 `json:"prices,omitempty"`
+}
+
+// StoragePrices holds Aurora cluster storage and I/O rates for one region.
+type StoragePrices struct {
+	StandardStorage    float64 `json:"standardStorage"`    // per GB-month, Aurora Standard
+	IOOptimizedStorage float64 `json:"ioOptimizedStorage"` // per GB-month, Aurora I/O-Optimized
+	StandardIO         float64 `json:"standardIO"`         // per million I/O requests, Aurora Standard
+}
+
+// StoragePricing maps region codes to Aurora storage rates.
+type StoragePricing map[string]StoragePrices
+
+// InstanceTypesSchemaVersion is the instance types file format written by
+// generate-types. Version 1 is the legacy bare map of classes.
+const InstanceTypesSchemaVersion = 2
+
+// InstanceTypesFile is a versioned instance types file: the classes, the Aurora
+// storage rates of each region, and metadata on how they were generated.
+type InstanceTypesFile struct {
+	SchemaVersion  int                   `json:"schemaVersion"`
+	Metadata       InstanceTypesMetadata `json:"metadata"`
+	InstanceTypes  InstanceTypes         `json:"instanceTypes"`
+	StoragePricing StoragePricing        `json:"storagePricing,omitempty"`
+
+	// Warnings describes validation errors found when the file was loaded that do
+	// not prevent analysis. It is not part of the file.
+	Warnings []string `json:"-"`
+}
+
+// InstanceTypesMetadata records how an instance types file was generated.
+type InstanceTypesMetadata struct {
+	GeneratedAt   time.Time `json:"generatedAt,omitzero"`
+	Generator     string    `json:"generator,omitempty"`
+	Engines       []string  `json:"engines,omitempty"`
+	HomeRegion    string    `json:"homeRegion,omitempty"`
+	TargetRegions []string  `json:"targetRegions,omitempty"`
+	// PricingSource is where the bulk pricing files were read from: the AWS
+	// pricing endpoint or a local pricing directory.
+	PricingSource string `json:"pricingSource,omitempty"`
+	// PricingPublished maps regions to the publicationDate of the bulk pricing
+	// file the prices were taken from.
+	PricingPublished map[string]string `json:"pricingPublished,omitempty"`
+}
+
+// InstanceTypesProvenance identifies the instance types an analysis used, for
+// tracing results back to a pricing snapshot.
+type InstanceTypesProvenance struct {
+	// Source is the URL or path the instance types were loaded from.
+	Source        string `json:"Source"`
+	SchemaVersion int    `json:"SchemaVersion"`
+	// Metadata is empty for legacy (version 1) files.
+	Metadata InstanceTypesMetadata `json:"Metadata,omitzero"`
+}
+
+// LatestPricingPublished returns the most recent bulk pricing publication date
+// ("" when unknown). Dates are ISO 8601, so they compare as strings.
+func (m InstanceTypesMetadata) LatestPricingPublished() string {
+	latest := ""
+	for _, published := range m.PricingPublished {
+		if published > latest {
+			latest = published
+		}
+	}
+	return latest
+}
+
+// Summary describes the provenance in one line, e.g. "types.json (schema v2,
+// generated 2026-10-19 04:17 UTC, 28 regions, pricing published 2026-10-01)".
+func (p *InstanceTypesProvenance) Summary() string {
+	if p == nil {
+		return ""
+	}
+	if p.SchemaVersion < 2 {
+		return p.Source + " (legacy format, no provenance)"
+	}
+
+	details := []string{fmt.Sprintf("schema v%d", p.SchemaVersion)}
+	m := p.Metadata
+	if !m.GeneratedAt.IsZero() {
+		details = append(details, "generated "+m.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	switch len(m.TargetRegions) {
+	case 0:
+	case 1:
+		details = append(details, "1 region")
+	default:
+		details = append(details, fmt.Sprintf("%d regions", len(m.TargetRegions)))
+	}
+	if published := m.LatestPricingPublished(); published != "" {
+		if t, err := time.Parse(time.RFC3339, published); err == nil {
+			published = t.UTC().Format(time.DateOnly)
+		}
+		details = append(details, "pricing published "+published)
+	}
+	return fmt.Sprintf("%s (%s)", p.Source, strings.Join(details, ", "))
+}
+
+// StoragePricingPath returns the location of the storage pricing file written
+// next to an instance types file or URL (e.g. "types.json" -> "types.storage.json").
+func StoragePricingPath(instanceTypesPath string) string {
+	return strings.TrimSuffix(instanceTypesPath, ".json") + ".storage.json"
+}
+
 // AvailableInRegion returns true if this instance type is available in the given region.
 // If the pricing map is nil (old format JSON), it assumes availability (backward compat).
 func (p InstanceProperties) AvailableInRegion(region string) bool {
@@ -80,18 +370,212 @@ func (p InstanceProperties) AvailableInRegion(region string) bool {
 	return ok
 }
 
+// SupportsEngineVersion returns true if the class is orderable with engineVersion
+// in the given region. Versions are matched at major/minor level against the
+// region's EngineVersions; files without them for the region fall back to
+// MinEngineVersion. An unknown engine version is assumed supported.
+func (p InstanceProperties) SupportsEngineVersion(region string, engineVersion string) bool {
+	if engineVersion == "" {
+		return true
+	}
+	if versions, ok := p.EngineVersions[region]; ok {
+		minor := util.EngineMinorVersion(engineVersion)
+		for _, v := range versions {
+			if v == minor {
+				return true
+			}
+		}
+		return false
+	}
+	return p.MeetsMinEngineVersion(engineVersion)
+}
+
+// MeetsMinEngineVersion returns true if engineVersion is at least the class's
+// MinEngineVersion, or the class has none.
+func (p InstanceProperties) MeetsMinEngineVersion(engineVersion string) bool {
+	if p.MinEngineVersion != "" {
+		return util.CompareVersions(engineVersion, p.MinEngineVersion) >= 0
+	}
+	return true
+}
+
+// ListsEngineVersion returns true if any class lists the major/minor version of
+// engineVersion in the region's EngineVersions.
+func (t InstanceTypes) ListsEngineVersion(region string, engineVersion string) bool {
+	minor := util.EngineMinorVersion(engineVersion)
+	for _, props := range t {
+		for _, v := range props.EngineVersions[region] {
+			if v == minor {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequiredEngineVersion returns the lowest engine version above engineVersion the
+// class is orderable with in the given region, or "" when there is none: a
+// major/minor version from EngineVersions, or MinEngineVersion without them.
+func (p InstanceProperties) RequiredEngineVersion(region string, engineVersion string) string {
+	if versions, ok := p.EngineVersions[region]; ok {
+		for _, v := range versions {
+			if util.CompareVersions(v, engineVersion) > 0 {
+				return v
+			}
+		}
+		return ""
+	}
+	if p.MinEngineVersion != "" && util.CompareVersions(p.MinEngineVersion, engineVersion) > 0 {
+		return p.MinEngineVersion
+	}
+	return ""
+}
+
+// BlockedGenerationUpgrade is a newer-generation class a recommendation could move
+// to instead, but only after an engine version upgrade.
+type BlockedGenerationUpgrade struct {
+	InstanceType string
+	// EngineVersion is the instance's engine version.
+	EngineVersion string
+	// RequiredEngineVersion is the lowest version InstanceType supports above it.
+	RequiredEngineVersion string
+	// MonthlySavings is the additional monthly saving over the recommended class.
+	MonthlySavings float64
+}
+
+func (b BlockedGenerationUpgrade) String() string {
+	return fmt.Sprintf("%s available from %s; you run %s", b.InstanceType, b.RequiredEngineVersion, b.EngineVersion)
+}
+
+// RemovesInstance reports whether the recommendation is to remove the instance
+// (Terminate, or LikelyIdle pending confirmation), saving its full cost.
+func (t RecommendationType) RemovesInstance() bool {
+	return t == Terminate || t == LikelyIdle
+}
+
+// IdleThresholds configures the LikelyIdle classifier. An instance whose peak
+// connections match one of the MonitoringConnections levels and whose activity
+// stays at or below every threshold is likely kept alive only by monitoring.
+// Rates are per second and use the analysis statistic.
+type IdleThresholds struct {
+	// MonitoringConnections are the connection counts held by monitoring agents
+	// alone. Empty disables the classifier.
+	MonitoringConnections []int
+	// CPU is the CPU utilization %.
+	CPU float64
+	// Queries, CommitThroughput and DMLThroughput are statements, commits and
+	// inserts/updates/deletes per second; engines not publishing one are not checked.
+	Queries          float64
+	CommitThroughput float64
+	DMLThroughput    float64
+	// IOPS is ReadIOPS plus WriteIOPS.
+	IOPS float64
+}
+
+// DefaultIdleThresholds returns the thresholds used when none are configured.
+func DefaultIdleThresholds() IdleThresholds {
+	return IdleThresholds{
+		MonitoringConnections: []int{1, 2},
+		CPU:                   5,
+		Queries:               5,
+		CommitThroughput:      1,
+		DMLThroughput:         0.1,
+		IOPS:                  20,
+	}
+}
+
+// IdleSignal is one metric behind a LikelyIdle recommendation: its value and the
+// threshold it stayed within (for DatabaseConnections, the allow-listed level it
+// matched).
+type IdleSignal struct {
+	Metric    string
+	Value     float64
+	Threshold float64
+}
+
+// DecisionStage is the part of the analysis that considered a candidate class.
+type DecisionStage string
+
+// Enum values for Decision Stage
+const (
+	ScaleDownStage           DecisionStage = "ScaleDown"
+	ScaleUpStage             DecisionStage = "ScaleUp"
+	GenerationUpgradeStage   DecisionStage = "GenerationUpgrade"
+	ClusterEqualizationStage DecisionStage = "ClusterEqualization"
+	PreviousGenerationStage  DecisionStage = "PreviousGeneration"
+)
+
+// DecisionCheck is one check performed on a candidate class, with the values it compared.
+type DecisionCheck struct {
+	Name   string
+	Value  string `json:"Value,omitempty"`
+	Passed bool
+}
+
+// DecisionStep records a candidate class considered for a recommendation, the
+// checks performed on it and why it was accepted or rejected.
+type DecisionStep struct {
+	Stage     DecisionStage
+	Candidate string
+	Checks    []DecisionCheck `json:"Checks,omitempty"`
+	Accepted  bool
+	Reason    string
+}
+
+// StorageConfigurationCost is a cluster's modeled monthly cost under one storage configuration.
+type StorageConfigurationCost struct {
+	Instances float64
+	Storage   float64
+	IO        float64
+	Total     float64
+}
+
+// StorageCostComparison models a cluster's monthly cost under Aurora Standard and
+// I/O-Optimized storage from its average volume size and billed I/O.
+type StorageCostComparison struct {
+	VolumeGiB         float64
+	MonthlyIORequests float64
+	Standard          StorageConfigurationCost
+	IOOptimized       StorageConfigurationCost
+}
+
 type Recommendation struct {
 	rdsTypes.Instance
 	Region                       string `json:"Region,omitempty"`
+	AccountId                    string `json:"AccountId,omitempty"`
 	Recommendation               RecommendationType
 	Reason                       RecommendationReason
 	RecommendedInstanceType      *string
 	MetricValue                  *float64
-	ProjectedCPU                 *float64 `json:"ProjectedCPU,omitempty"`
-	MaxConnectionsAdjustRequired bool     `json:"MaxConnectionsAdjustRequired,omitempty"`
-	PeakConnections              *float64 `json:"PeakConnections,omitempty"`
-	ClusterEqualized             bool     `json:"ClusterEqualized,omitempty"`
+	ProjectedCPU                 *float64     `json:"ProjectedCPU,omitempty"`
+	MaxConnectionsAdjustRequired bool         `json:"MaxConnectionsAdjustRequired,omitempty"`
+	PeakConnections              *float64     `json:"PeakConnections,omitempty"`
+	ClusterEqualized             bool         `json:"ClusterEqualized,omitempty"`
+	PricingModel                 PricingModel `json:"PricingModel,omitempty"`
 	MonthlyApproximatePriceDiff  *float64
+	Metrics                      *RecommendationMetrics     `json:"Metrics,omitempty"`
+	SkipReason                   string                     `json:"SkipReason,omitempty"`
+	Confidence                   *float64                   `json:"Confidence,omitempty"`
+	ConfidenceReasons            []string                   `json:"ConfidenceReasons,omitempty"`
+	ShortMetricsWindow           bool                       `json:"ShortMetricsWindow,omitempty"`
+	RunningInstanceClass         *string                    `json:"RunningInstanceClass,omitempty"`
+	RunningEngineVersion         *string                    `json:"RunningEngineVersion,omitempty"`
+	OnDemandPriceFallback        bool                       `json:"OnDemandPriceFallback,omitempty"`
+	IdleEvidence                 []IdleSignal               `json:"IdleEvidence,omitempty"`
+	ListMonthlyPriceDiff         *float64                   `json:"ListMonthlyPriceDiff,omitempty"`
+	CURHourlyRate                *float64                   `json:"CURHourlyRate,omitempty"`
+	CURDiscountRatio             *float64                   `json:"CURDiscountRatio,omitempty"`
+	EffectiveMonthlyPriceDiff    *float64                   `json:"EffectiveMonthlyPriceDiff,omitempty"`
+	ReservedCoverage             *float64                   `json:"ReservedCoverage,omitempty"`
+	ReservedInstanceWarning      string                     `json:"ReservedInstanceWarning,omitempty"`
+	PreviousGeneration           bool                       `json:"PreviousGeneration,omitempty"`
+	EndOfLife                    string                     `json:"EndOfLife,omitempty"`
+	BlockedGenerationUpgrade     *BlockedGenerationUpgrade  `json:"BlockedGenerationUpgrade,omitempty"`
+	DecisionTrace                []DecisionStep             `json:"DecisionTrace,omitempty"`
+	RecommendedStorageType       *string                    `json:"RecommendedStorageType,omitempty"`
+	StorageCost                  *StorageCostComparison     `json:"StorageCost,omitempty"`
+	Currency                     *currency.Currency         `json:"Currency,omitempty"`
+	InstanceTypesProvenance      *InstanceTypesProvenance   `json:"InstanceTypesProvenance,omitempty"`
 	CurrentInstanceProperties    *InstanceProperties        `json:"-"`
 	TargetInstanceProperties     *InstanceProperties        `json:"-"`
 	TimeSeriesMetrics            *cwTypes.TimeSeriesMetrics `json:"-"`
@@ -0,0 +1,239 @@
+package rds_right_size
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+)
+
+// ValidationSeverity tells whether a validation issue prevents analysis.
+type ValidationSeverity string
+
+const (
+	// SeverityError issues fail validate-types. LoadInstanceTypes only rejects the
+	// file for those in loadBlockingChecks and reports the rest as warnings.
+	SeverityError ValidationSeverity = "error"
+	// SeverityWarning issues are reported by validate-types but do not block analysis.
+	SeverityWarning ValidationSeverity = "warning"
+)
+
+// Validation checks, reported in ValidationIssue.Check.
+const (
+	CheckEmpty          = "empty"
+	CheckZeroVcpuMem    = "zero-vcpu-mem"
+	CheckDangling       = "dangling-pointer"
+	CheckCycle          = "cyclic-pointer"
+	CheckAsymmetricLink = "asymmetric-link"
+	CheckNonMonotonic   = "non-monotonic"
+	CheckMissingPrice   = "missing-price"
+	CheckMixedKeys      = "mixed-keys"
+)
+
+// loadBlockingChecks are the errors that make LoadInstanceTypes reject a file, as
+// analysis cannot run on it: no classes at all, or chains that never end. Files
+// accepted before validation existed (legacy maps included) keep loading with
+// their other errors reported as warnings.
+var loadBlockingChecks = map[string]bool{
+	CheckEmpty: true,
+	CheckCycle: true,
+}
+
+// ValidationIssue is one problem found in an instance types file.
+type ValidationIssue struct {
+	Severity ValidationSeverity `json:"severity"`
+	Check    string             `json:"check"`
+	Key      string             `json:"key,omitempty"`
+	Message  string             `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	if i.Key == "" {
+		return i.Message
+	}
+	return i.Key + ": " + i.Message
+}
+
+// ValidateInstanceTypes checks an instance types map for problems that break or
+// skew analysis: missing vCPU/memory, Up/Down pointers to missing keys or forming
+// cycles, chains whose vCPU or memory shrink when scaling up, missing prices, and
+// a mix of engine-prefixed and plain keys. Issues are sorted by key and check.
+func ValidateInstanceTypes(instanceTypes types.InstanceTypes) []ValidationIssue {
+	if len(instanceTypes) == 0 {
+		return []ValidationIssue{{Severity: SeverityError, Check: CheckEmpty, Message: "no instance types found"}}
+	}
+
+	var issues []ValidationIssue
+	add := func(severity ValidationSeverity, check, key, format string, args ...any) {
+		issues = append(issues, ValidationIssue{Severity: severity, Check: check, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Plain keys are found before engine-prefixed ones, so a mix makes one engine's
+	// classes resolve to the other's properties
+	var prefixed, plain int
+	for key := range instanceTypes {
+		if strings.Contains(key, ":") {
+			prefixed++
+		} else {
+			plain++
+		}
+	}
+	if prefixed > 0 && plain > 0 {
+		add(SeverityError, CheckMixedKeys, "", "%d engine-prefixed and %d plain keys are mixed; use one form (regenerate with a single --engine or both)", prefixed, plain)
+	}
+
+	for key, props := range instanceTypes {
+		if props.Vcpu <= 0 || props.Mem <= 0 {
+			add(SeverityError, CheckZeroVcpuMem, key, "vcpu (%d) and mem (%d) must be positive", props.Vcpu, props.Mem)
+		}
+
+		for _, link := range []struct {
+			name    string
+			target  *string
+			reverse func(types.InstanceProperties) *string
+			larger  bool
+		}{
+			{"up", props.Up, func(p types.InstanceProperties) *string { return p.Down }, true},
+			{"down", props.Down, func(p types.InstanceProperties) *string { return p.Up }, false},
+		} {
+			if link.target == nil {
+				continue
+			}
+			target := *link.target
+			targetProps, ok := instanceTypes[target]
+			if !ok {
+				add(SeverityError, CheckDangling, key, "%s points to missing %s", link.name, target)
+				continue
+			}
+			if engine, targetEngine := enginePrefix(key), enginePrefix(target); engine != targetEngine {
+				add(SeverityError, CheckMixedKeys, key, "%s points to %s of another engine", link.name, target)
+			}
+			if back := link.reverse(targetProps); back == nil || *back != key {
+				add(SeverityWarning, CheckAsymmetricLink, key, "%s points to %s, which does not point back", link.name, target)
+			}
+
+			// A down link mirrored by the target's up link was already checked from there
+			if !link.larger && targetProps.Up != nil && *targetProps.Up == key {
+				continue
+			}
+			smaller, larger := props, targetProps
+			if !link.larger {
+				smaller, larger = targetProps, props
+			}
+			switch {
+			case larger.Vcpu < smaller.Vcpu || larger.Mem < smaller.Mem:
+				add(SeverityError, CheckNonMonotonic, key, "%s %s shrinks to %d vCPU / %d GiB from %d vCPU / %d GiB", link.name, target, targetProps.Vcpu, targetProps.Mem, props.Vcpu, props.Mem)
+			case larger.Vcpu == smaller.Vcpu && larger.Mem == smaller.Mem:
+				add(SeverityWarning, CheckNonMonotonic, key, "%s %s has the same %d vCPU / %d GiB", link.name, target, props.Vcpu, props.Mem)
+			}
+		}
+
+		if props.Pricing == nil && props.StdPrice <= 0 {
+			add(SeverityWarning, CheckMissingPrice, key, "no pricing and no stdPrice; costs will be reported as zero")
+		}
+		for region, price := range props.Pricing {
+			if price <= 0 {
+				add(SeverityError, CheckMissingPrice, key, "listed in %s without a positive price", region)
+			}
+		}
+		for _, region := range sortedRegions(props.ReservedPricing) {
+			if _, ok := props.Pricing[region]; !ok {
+				add(SeverityWarning, CheckMissingPrice, key, "reserved pricing for %s without an on-demand price", region)
+			}
+		}
+		for _, region := range sortedRegions(props.IOOptimizedPricing) {
+			if _, ok := props.Pricing[region]; !ok {
+				add(SeverityWarning, CheckMissingPrice, key, "I/O-Optimized pricing for %s without an on-demand price", region)
+			}
+		}
+	}
+
+	for _, key := range pointerCycles(instanceTypes) {
+		add(SeverityError, CheckCycle, key, "up/down pointers form a cycle")
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Key != issues[j].Key {
+			return issues[i].Key < issues[j].Key
+		}
+		if issues[i].Check != issues[j].Check {
+			return issues[i].Check < issues[j].Check
+		}
+		return issues[i].Message < issues[j].Message
+	})
+	return issues
+}
+
+// ValidationErrors returns the issues of SeverityError.
+func ValidationErrors(issues []ValidationIssue) []ValidationIssue {
+	var errs []ValidationIssue
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+// pointerCycles returns the sorted keys whose Up or Down chain revisits a key
+// before ending. Each cycle is reported once, at its smallest key.
+func pointerCycles(instanceTypes types.InstanceTypes) []string {
+	cycles := make(map[string]bool)
+	for _, next := range []func(types.InstanceProperties) *string{
+		func(p types.InstanceProperties) *string { return p.Up },
+		func(p types.InstanceProperties) *string { return p.Down },
+	} {
+		for start := range instanceTypes {
+			seen := map[string]bool{}
+			key := start
+			for {
+				if seen[key] {
+					cycles[smallestInCycle(instanceTypes, key, next)] = true
+					break
+				}
+				seen[key] = true
+				props, ok := instanceTypes[key]
+				if !ok || next(props) == nil {
+					break
+				}
+				key = *next(props)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(cycles))
+	for key := range cycles {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// smallestInCycle returns the smallest key of the cycle containing key.
+func smallestInCycle(instanceTypes types.InstanceTypes, key string, next func(types.InstanceProperties) *string) string {
+	smallest := key
+	for k := *next(instanceTypes[key]); k != key; k = *next(instanceTypes[k]) {
+		if k < smallest {
+			smallest = k
+		}
+	}
+	return smallest
+}
+
+// enginePrefix returns the engine prefix of a key ("" for plain keys).
+func enginePrefix(key string) string {
+	if idx := strings.Index(key, ":"); idx >= 0 {
+		return key[:idx]
+	}
+	return ""
+}
+
+func sortedRegions[V any](m map[string]V) []string {
+	regions := make([]string, 0, len(m))
+	for region := range m {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
@@ -0,0 +1,127 @@
+package rds_right_size
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
+	rdsTypes "github.com/luneo7/rds-right-size/internal/rds/types"
+)
+
+// DefaultMinWindowDays is the default minimum number of days of metrics left after
+// an instance class change before the recommendation is flagged.
+const DefaultMinWindowDays = 7
+
+const (
+	// eventRetentionDays is how far back RDS keeps events
+	eventRetentionDays = 14
+
+	// memoryStepRatio is the ratio between the daily maximum FreeableMemory levels
+	// before and after a day that marks it as an instance class change
+	memoryStepRatio = 1.5
+
+	// minStepDays is the number of days required on each side of a FreeableMemory step
+	minStepDays = 2
+)
+
+// setClassChanges records the most recent instance class change of each instance
+// from the region's RDS events.
+func (r *RDSRightSize) setClassChanges(ctx context.Context, instances []rdsTypes.Instance) error {
+	if len(instances) == 0 {
+		return nil
+	}
+
+	changes, err := r.rds.GetInstanceClassChanges(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to describe DB instance events: %w", err)
+	}
+
+	for i := range instances {
+		if instances[i].DBInstanceIdentifier == nil {
+			continue
+		}
+		if changed, ok := changes[*instances[i].DBInstanceIdentifier]; ok {
+			changedCopy := changed
+			instances[i].LastClassChange = &changedCopy
+		}
+	}
+	return nil
+}
+
+// lastClassChange returns the most recent instance class change within the
+// lookback period and how it was found, or nil when there was none. RDS events only
+// cover the last 14 days; for longer periods an older change is detected from a
+// step in the daily maximum FreeableMemory, as resizing changes the instance memory.
+func (r *RDSRightSize) lastClassChange(instance *rdsTypes.Instance, metrics *cwTypes.Metrics, now time.Time) (*time.Time, types.ClassChangeSource) {
+	lookbackStart := now.AddDate(0, 0, -r.period)
+
+	if instance.LastClassChange != nil {
+		if instance.LastClassChange.After(lookbackStart) {
+			return instance.LastClassChange, types.ClassChangeEvent
+		}
+		return nil, ""
+	}
+
+	if r.period <= eventRetentionDays {
+		return nil, ""
+	}
+
+	step, ok := freeableMemoryStep(metrics.FreeableMemoryDaily)
+	// A change within the event retention would have been reported as an event
+	if !ok || step.After(now.AddDate(0, 0, -eventRetentionDays)) {
+		return nil, ""
+	}
+	return &step, types.ClassChangeFreeableMemory
+}
+
+// freeableMemoryStep finds the day that best splits the daily maximum FreeableMemory
+// into two levels and returns the start of the first full day at the new level,
+// when the levels differ by at least memoryStepRatio.
+func freeableMemoryStep(points []cwTypes.TimeSeriesDataPoint) (time.Time, bool) {
+	if len(points) < 2*minStepDays {
+		return time.Time{}, false
+	}
+
+	best, bestCost := -1, math.Inf(1)
+	for k := minStepDays; k <= len(points)-minStepDays; k++ {
+		cost := squaredDeviation(points[:k]) + squaredDeviation(points[k:])
+		if cost < bestCost {
+			best, bestCost = k, cost
+		}
+	}
+
+	before, after := meanValue(points[:best]), meanValue(points[best:])
+	if before <= 0 || after <= 0 {
+		return time.Time{}, false
+	}
+	ratio := after / before
+	if ratio < 1 {
+		ratio = 1 / ratio
+	}
+	if ratio < memoryStepRatio {
+		return time.Time{}, false
+	}
+
+	// The day of the change mixes both levels
+	return points[best].Timestamp.Add(24 * time.Hour), true
+}
+
+func meanValue(points []cwTypes.TimeSeriesDataPoint) float64 {
+	var sum float64
+	for _, p := range points {
+		sum += p.Value
+	}
+	return sum / float64(len(points))
+}
+
+func squaredDeviation(points []cwTypes.TimeSeriesDataPoint) float64 {
+	mean := meanValue(points)
+	var sum float64
+	for _, p := range points {
+		sum += (p.Value - mean) * (p.Value - mean)
+	}
+	return sum
+}
@@ -3,9 +3,12 @@ package rds
 import (
 	"context"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/luneo7/rds-right-size/internal/rds/types"
 )
 
@@ -44,16 +47,27 @@ func (r *RDS) GetInstances(ctx context.Context) ([]types.Instance, error) {
 					paramGroupName = v.DBParameterGroups[0].DBParameterGroupName
 				}
 
+				var pending *types.PendingModifiedValues
+				if p := v.PendingModifiedValues; p != nil && (p.DBInstanceClass != nil || p.EngineVersion != nil) {
+					pending = &types.PendingModifiedValues{
+						DBInstanceClass: p.DBInstanceClass,
+						EngineVersion:   p.EngineVersion,
+					}
+				}
+
 				b[i] = types.Instance{
-					AvailabilityZone:     v.AvailabilityZone,
-					DBInstanceArn:        v.DBInstanceArn,
-					DBInstanceIdentifier: v.DBInstanceIdentifier,
-					DBInstanceClass:      v.DBInstanceClass,
-					Engine:               v.Engine,
-					EngineVersion:        v.EngineVersion,
-					DBParameterGroupName: paramGroupName,
-					DBClusterIdentifier:  v.DBClusterIdentifier,
-					Tags:                 tags,
+					AvailabilityZone:      v.AvailabilityZone,
+					DBInstanceArn:         v.DBInstanceArn,
+					DBInstanceIdentifier:  v.DBInstanceIdentifier,
+					DBInstanceClass:       v.DBInstanceClass,
+					DBInstanceStatus:      v.DBInstanceStatus,
+					PendingModifiedValues: pending,
+					Engine:                v.Engine,
+					EngineVersion:         v.EngineVersion,
+					DBParameterGroupName:  paramGroupName,
+					DBClusterIdentifier:   v.DBClusterIdentifier,
+					InstanceCreateTime:    v.InstanceCreateTime,
+					Tags:                  tags,
 				}
 			}
 			dbInstances = append(dbInstances, b...)
@@ -65,6 +79,73 @@ func (r *RDS) GetInstances(ctx context.Context) ([]types.Instance, error) {
 	return dbInstances, nil
 }
 
+// GetClusterStorageTypes returns the storage type of every DB cluster in the
+// configured region, keyed by cluster identifier. Aurora clusters created before
+// I/O-Optimized existed may report no storage type; those map to "aurora".
+func (r *RDS) GetClusterStorageTypes(ctx context.Context) (map[string]string, error) {
+	storageTypes := make(map[string]string)
+
+	paginator := awsRds.NewDescribeDBClustersPaginator(r.rdsClient, &awsRds.DescribeDBClustersInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range output.DBClusters {
+			if v.DBClusterIdentifier == nil {
+				continue
+			}
+			storageType := "aurora"
+			if v.StorageType != nil && *v.StorageType != "" {
+				storageType = *v.StorageType
+			}
+			storageTypes[*v.DBClusterIdentifier] = storageType
+		}
+	}
+
+	return storageTypes, nil
+}
+
+// eventRetention is how far back RDS keeps events, in minutes (14 days).
+const eventRetention = 14 * 24 * 60
+
+// GetInstanceClassChanges returns the time of the most recent instance class change
+// of every DB instance in the configured region, keyed by instance identifier.
+// Only changes within the retention of RDS events (14 days) are known.
+func (r *RDS) GetInstanceClassChanges(ctx context.Context) (map[string]time.Time, error) {
+	changes := make(map[string]time.Time)
+
+	paginator := awsRds.NewDescribeEventsPaginator(r.rdsClient, &awsRds.DescribeEventsInput{
+		Duration:        aws.Int32(eventRetention),
+		SourceType:      rdsTypes.SourceTypeDbInstance,
+		EventCategories: []string{"configuration change"},
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range output.Events {
+			if v.SourceIdentifier == nil || v.Date == nil || v.Message == nil {
+				continue
+			}
+			// e.g. "Finished applying modification to DB instance class"
+			if !strings.Contains(strings.ToLower(*v.Message), "instance class") {
+				continue
+			}
+			if last, ok := changes[*v.SourceIdentifier]; !ok || v.Date.After(last) {
+				changes[*v.SourceIdentifier] = *v.Date
+			}
+		}
+	}
+
+	return changes, nil
+}
+
 // GetMaxConnections queries the DB parameter group for the max_connections setting.
 // Returns the numeric value if explicitly set to a static number, or nil if it's
 // a formula, unset, or if the API call fails. The caller should fall back to the
@@ -105,3 +186,45 @@ func (r *RDS) GetMaxConnections(ctx context.Context, paramGroupName *string) (*i
 
 	return nil, nil
 }
+
+// GetReservedInstances returns all active reserved DB instances in the configured region.
+func (r *RDS) GetReservedInstances(ctx context.Context) ([]types.ReservedInstance, error) {
+	var reserved []types.ReservedInstance
+
+	paginator := awsRds.NewDescribeReservedDBInstancesPaginator(r.rdsClient, &awsRds.DescribeReservedDBInstancesInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range output.ReservedDBInstances {
+			if v.State == nil || *v.State != "active" {
+				continue
+			}
+
+			var count int32
+			if v.DBInstanceCount != nil {
+				count = *v.DBInstanceCount
+			}
+
+			var duration time.Duration
+			if v.Duration != nil {
+				duration = time.Duration(*v.Duration) * time.Second
+			}
+
+			reserved = append(reserved, types.ReservedInstance{
+				ReservedDBInstanceId: v.ReservedDBInstanceId,
+				DBInstanceClass:      v.DBInstanceClass,
+				ProductDescription:   v.ProductDescription,
+				DBInstanceCount:      count,
+				StartTime:            v.StartTime,
+				Duration:             duration,
+				State:                v.State,
+			})
+		}
+	}
+
+	return reserved, nil
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type Instance struct {
 	// The Availability Zone that the automated backup was created in. For information
 	// on Amazon Web Services Regions and Availability Zones, see Regions and
@@ -17,6 +19,13 @@ type Instance struct {
 	// The name of the compute and memory capacity class of the DB instance.
 	DBInstanceClass *string
 
+	// The current state of the DB instance ("available", "stopped", "modifying", ...).
+	DBInstanceStatus *string
+
+	// Changes to the DB instance that are being applied or are scheduled for the
+	// next maintenance window.
+	PendingModifiedValues *PendingModifiedValues
+
 	// The name of the database engine for this automated backup.
 	Engine *string
 
@@ -29,7 +38,61 @@ type Instance struct {
 	// The identifier of the DB cluster that the instance belongs to (Aurora only).
 	DBClusterIdentifier *string
 
+	// The storage type of the DB cluster the instance belongs to ("aurora" for
+	// Aurora Standard, "aurora-iopt1" for Aurora I/O-Optimized). Populated from
+	// DescribeDBClusters; nil for instances outside a cluster.
+	StorageType *string
+
+	// The time the DB instance was created.
+	InstanceCreateTime *time.Time
+
+	// The time of the most recent instance class change, if any happened within
+	// the retention of RDS events (14 days). Populated from DescribeEvents.
+	LastClassChange *time.Time
+
 	Tags Tags
 }
 
 type Tags map[string]string
+
+// PendingModifiedValues describes the pending changes of a DB instance relevant to
+// right-sizing.
+type PendingModifiedValues struct {
+	// The instance class the DB instance will change to.
+	DBInstanceClass *string
+
+	// The engine version the DB instance will be upgraded to.
+	EngineVersion *string
+}
+
+// ReservedInstance describes an RDS reserved DB instance purchase.
+type ReservedInstance struct {
+	// The unique identifier for the reservation.
+	ReservedDBInstanceId *string
+
+	// The DB instance class the reservation was purchased for.
+	DBInstanceClass *string
+
+	// The engine the reservation applies to (e.g. "aurora-mysql", "aurora-postgresql").
+	ProductDescription *string
+
+	// The number of reserved instances purchased.
+	DBInstanceCount int32
+
+	// The time the reservation started.
+	StartTime *time.Time
+
+	// The duration of the reservation.
+	Duration time.Duration
+
+	// The state of the reservation ("active", "retired", "payment-pending", ...).
+	State *string
+}
+
+// EndTime returns the time the reservation expires, or the zero time when unknown.
+func (r ReservedInstance) EndTime() time.Time {
+	if r.StartTime == nil {
+		return time.Time{}
+	}
+	return r.StartTime.Add(r.Duration)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
 )
 
 const (
@@ -18,20 +19,48 @@ const (
 	fieldCPUUpsize
 	fieldCPUDownsize
 	fieldMemUpsize
+	fieldMinConfidence
 	fieldStat
 	fieldPreferNewGen
+	fieldRICoverage
+	fieldStorageAnalysis
+	fieldIncludeOptimized
+	fieldPricingModel
 	fieldInstanceTypes
+	fieldCURFile
+	fieldPricingOverrides
+	fieldCurrency
+	fieldExchangeRates
+	fieldAccounts
+	fieldRoleName
 	fieldSubmit
 )
 
 var statOptions = []string{"p99", "p95", "p50", "Average"}
 var preferNewGenOptions = []string{"Off", "On"}
+var riCoverageOptions = []string{"Off", "On"}
+
+var storageAnalysisOptions = []string{"Off", "On"}
+var includeOptimizedOptions = []string{"Off", "On"}
+
+// pricingModelOptions mirrors types.PricingModels for the cycling selector.
+var pricingModelOptions = func() []string {
+	options := make([]string, len(types.PricingModels))
+	for i, m := range types.PricingModels {
+		options[i] = string(m)
+	}
+	return options
+}()
 
 type ConfigModel struct {
 	inputs            []textinput.Model
 	focusIndex        int
 	statIndex         int
 	preferNewGenIndex int
+	riCoverageIndex   int
+	storageIndex      int
+	optimizedIndex    int
+	pricingModelIndex int
 	err               error
 	width             int
 	height            int
@@ -41,20 +70,33 @@ type ConfigModel struct {
 }
 
 type ConfigValues struct {
-	Profile          string
-	Region           string
-	Tags             string
-	Period           int
-	CPUUpsize        float64
-	CPUDownsize      float64
-	MemUpsize        float64
-	Stat             string
-	PreferNewGen     bool
-	InstanceTypesURL string
+	Profile              string
+	Region               string
+	Tags                 string
+	Period               int
+	CPUUpsize            float64
+	CPUDownsize          float64
+	MemUpsize            float64
+	MinConfidence        float64
+	MinWindowDays        int
+	IdleThresholds       *types.IdleThresholds
+	Stat                 string
+	PreferNewGen         bool
+	RICoverage           bool
+	StorageAnalysis      bool
+	IncludeOptimized     bool
+	PricingModel         string
+	InstanceTypesURL     string
+	CURFile              string
+	PricingOverridesFile string
+	Currency             string
+	ExchangeRatesFile    string
+	Accounts             string
+	RoleName             string
 }
 
 func NewConfigModel(defaults ConfigValues) ConfigModel {
-	inputs := make([]textinput.Model, 10)
+	inputs := make([]textinput.Model, 21)
 
 	// Profile
 	inputs[fieldProfile] = textinput.New()
@@ -115,6 +157,14 @@ func NewConfigModel(defaults ConfigValues) ConfigModel {
 	}
 
 	// Stat (cycling, not a text input - but we use a text input as display)
+	inputs[fieldMinConfidence] = textinput.New()
+	inputs[fieldMinConfidence].Placeholder = "0"
+	inputs[fieldMinConfidence].CharLimit = 6
+	inputs[fieldMinConfidence].Width = 40
+	if defaults.MinConfidence > 0 {
+		inputs[fieldMinConfidence].SetValue(fmt.Sprintf("%.0f", defaults.MinConfidence))
+	}
+
 	inputs[fieldStat] = textinput.New()
 	inputs[fieldStat].Placeholder = "p99"
 	inputs[fieldStat].CharLimit = 10
@@ -126,6 +176,30 @@ func NewConfigModel(defaults ConfigValues) ConfigModel {
 	inputs[fieldPreferNewGen].CharLimit = 5
 	inputs[fieldPreferNewGen].Width = 40
 
+	// RI Coverage (cycling selector)
+	inputs[fieldRICoverage] = textinput.New()
+	inputs[fieldRICoverage].Placeholder = "Off"
+	inputs[fieldRICoverage].CharLimit = 5
+	inputs[fieldRICoverage].Width = 40
+
+	// Storage Analysis (cycling selector)
+	inputs[fieldStorageAnalysis] = textinput.New()
+	inputs[fieldStorageAnalysis].Placeholder = "Off"
+	inputs[fieldStorageAnalysis].CharLimit = 5
+	inputs[fieldStorageAnalysis].Width = 40
+
+	// Include Optimized (cycling selector)
+	inputs[fieldIncludeOptimized] = textinput.New()
+	inputs[fieldIncludeOptimized].Placeholder = "Off"
+	inputs[fieldIncludeOptimized].CharLimit = 5
+	inputs[fieldIncludeOptimized].Width = 40
+
+	// Pricing Model (cycling selector)
+	inputs[fieldPricingModel] = textinput.New()
+	inputs[fieldPricingModel].Placeholder = "on-demand"
+	inputs[fieldPricingModel].CharLimit = 24
+	inputs[fieldPricingModel].Width = 40
+
 	// Instance types URL
 	inputs[fieldInstanceTypes] = textinput.New()
 	inputs[fieldInstanceTypes].Placeholder = "https://... or /path/to/file.json"
@@ -133,6 +207,42 @@ func NewConfigModel(defaults ConfigValues) ConfigModel {
 	inputs[fieldInstanceTypes].Width = 40
 	inputs[fieldInstanceTypes].SetValue(defaults.InstanceTypesURL)
 
+	inputs[fieldCURFile] = textinput.New()
+	inputs[fieldCURFile].Placeholder = "optional: /path/to/cur.csv.gz or .parquet"
+	inputs[fieldCURFile].CharLimit = 512
+	inputs[fieldCURFile].Width = 40
+	inputs[fieldCURFile].SetValue(defaults.CURFile)
+
+	inputs[fieldPricingOverrides] = textinput.New()
+	inputs[fieldPricingOverrides].Placeholder = "optional: /path/to/pricing-overrides.json"
+	inputs[fieldPricingOverrides].CharLimit = 512
+	inputs[fieldPricingOverrides].Width = 40
+	inputs[fieldPricingOverrides].SetValue(defaults.PricingOverridesFile)
+
+	inputs[fieldCurrency] = textinput.New()
+	inputs[fieldCurrency].Placeholder = "USD"
+	inputs[fieldCurrency].CharLimit = 3
+	inputs[fieldCurrency].Width = 40
+	inputs[fieldCurrency].SetValue(defaults.Currency)
+
+	inputs[fieldExchangeRates] = textinput.New()
+	inputs[fieldExchangeRates].Placeholder = "required for non-USD: /path/to/rates.json"
+	inputs[fieldExchangeRates].CharLimit = 512
+	inputs[fieldExchangeRates].Width = 40
+	inputs[fieldExchangeRates].SetValue(defaults.ExchangeRatesFile)
+
+	inputs[fieldAccounts] = textinput.New()
+	inputs[fieldAccounts].Placeholder = "optional: 111111111111,222222222222 or ou-abcd-12345678"
+	inputs[fieldAccounts].CharLimit = 1024
+	inputs[fieldAccounts].Width = 40
+	inputs[fieldAccounts].SetValue(defaults.Accounts)
+
+	inputs[fieldRoleName] = textinput.New()
+	inputs[fieldRoleName].Placeholder = "role assumed in each account"
+	inputs[fieldRoleName].CharLimit = 128
+	inputs[fieldRoleName].Width = 40
+	inputs[fieldRoleName].SetValue(defaults.RoleName)
+
 	// Find stat index
 	statIdx := 0
 	for i, s := range statOptions {
@@ -148,11 +258,39 @@ func NewConfigModel(defaults ConfigValues) ConfigModel {
 		preferNewGenIdx = 1
 	}
 
+	riCoverageIdx := 0
+	if defaults.RICoverage {
+		riCoverageIdx = 1
+	}
+
+	storageIdx := 0
+	if defaults.StorageAnalysis {
+		storageIdx = 1
+	}
+
+	optimizedIdx := 0
+	if defaults.IncludeOptimized {
+		optimizedIdx = 1
+	}
+
+	// Find pricing model index
+	pricingModelIdx := 0
+	for i, pm := range pricingModelOptions {
+		if pm == defaults.PricingModel {
+			pricingModelIdx = i
+			break
+		}
+	}
+
 	return ConfigModel{
 		inputs:            inputs,
 		focusIndex:        0,
 		statIndex:         statIdx,
 		preferNewGenIndex: preferNewGenIdx,
+		riCoverageIndex:   riCoverageIdx,
+		storageIndex:      storageIdx,
+		optimizedIndex:    optimizedIdx,
+		pricingModelIndex: pricingModelIdx,
 		defaults:          defaults,
 	}
 }
@@ -233,6 +371,34 @@ func (m ConfigModel) Update(msg tea.Msg) (ConfigModel, tea.Cmd) {
 				}
 				return m, nil
 			}
+			if m.focusIndex == fieldRICoverage {
+				m.riCoverageIndex--
+				if m.riCoverageIndex < 0 {
+					m.riCoverageIndex = len(riCoverageOptions) - 1
+				}
+				return m, nil
+			}
+			if m.focusIndex == fieldStorageAnalysis {
+				m.storageIndex--
+				if m.storageIndex < 0 {
+					m.storageIndex = len(storageAnalysisOptions) - 1
+				}
+				return m, nil
+			}
+			if m.focusIndex == fieldIncludeOptimized {
+				m.optimizedIndex--
+				if m.optimizedIndex < 0 {
+					m.optimizedIndex = len(includeOptimizedOptions) - 1
+				}
+				return m, nil
+			}
+			if m.focusIndex == fieldPricingModel {
+				m.pricingModelIndex--
+				if m.pricingModelIndex < 0 {
+					m.pricingModelIndex = len(pricingModelOptions) - 1
+				}
+				return m, nil
+			}
 
 		case "right":
 			if m.focusIndex == fieldStat {
@@ -249,6 +415,34 @@ func (m ConfigModel) Update(msg tea.Msg) (ConfigModel, tea.Cmd) {
 				}
 				return m, nil
 			}
+			if m.focusIndex == fieldRICoverage {
+				m.riCoverageIndex++
+				if m.riCoverageIndex >= len(riCoverageOptions) {
+					m.riCoverageIndex = 0
+				}
+				return m, nil
+			}
+			if m.focusIndex == fieldStorageAnalysis {
+				m.storageIndex++
+				if m.storageIndex >= len(storageAnalysisOptions) {
+					m.storageIndex = 0
+				}
+				return m, nil
+			}
+			if m.focusIndex == fieldIncludeOptimized {
+				m.optimizedIndex++
+				if m.optimizedIndex >= len(includeOptimizedOptions) {
+					m.optimizedIndex = 0
+				}
+				return m, nil
+			}
+			if m.focusIndex == fieldPricingModel {
+				m.pricingModelIndex++
+				if m.pricingModelIndex >= len(pricingModelOptions) {
+					m.pricingModelIndex = 0
+				}
+				return m, nil
+			}
 
 		case "enter":
 			if m.focusIndex == fieldStat {
@@ -259,15 +453,31 @@ func (m ConfigModel) Update(msg tea.Msg) (ConfigModel, tea.Cmd) {
 				m.preferNewGenIndex = (m.preferNewGenIndex + 1) % len(preferNewGenOptions)
 				return m, nil
 			}
+			if m.focusIndex == fieldRICoverage {
+				m.riCoverageIndex = (m.riCoverageIndex + 1) % len(riCoverageOptions)
+				return m, nil
+			}
+			if m.focusIndex == fieldStorageAnalysis {
+				m.storageIndex = (m.storageIndex + 1) % len(storageAnalysisOptions)
+				return m, nil
+			}
+			if m.focusIndex == fieldIncludeOptimized {
+				m.optimizedIndex = (m.optimizedIndex + 1) % len(includeOptimizedOptions)
+				return m, nil
+			}
+			if m.focusIndex == fieldPricingModel {
+				m.pricingModelIndex = (m.pricingModelIndex + 1) % len(pricingModelOptions)
+				return m, nil
+			}
 			// Submit is handled by the parent model
 		}
 	}
 
 	// Update text inputs (skip cycling fields)
-	if m.focusIndex != fieldStat && m.focusIndex != fieldPreferNewGen && m.focusIndex != fieldSubmit {
+	if m.focusIndex != fieldStat && m.focusIndex != fieldPreferNewGen && m.focusIndex != fieldRICoverage && m.focusIndex != fieldStorageAnalysis && m.focusIndex != fieldIncludeOptimized && m.focusIndex != fieldPricingModel && m.focusIndex != fieldSubmit {
 		cmds := make([]tea.Cmd, len(m.inputs))
 		for i := range m.inputs {
-			if i == fieldStat || i == fieldPreferNewGen {
+			if i == fieldStat || i == fieldPreferNewGen || i == fieldRICoverage || i == fieldStorageAnalysis || i == fieldIncludeOptimized || i == fieldPricingModel {
 				continue
 			}
 			m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
@@ -311,9 +521,20 @@ func (m ConfigModel) View() string {
 		{"CPU Upsize %", fieldCPUUpsize},
 		{"CPU Downsize %", fieldCPUDownsize},
 		{"Mem Upsize %", fieldMemUpsize},
+		{"Min Confidence", fieldMinConfidence},
 		{"Statistic", fieldStat},
 		{"Prefer New Gen", fieldPreferNewGen},
+		{"RI Coverage", fieldRICoverage},
+		{"Storage Analysis", fieldStorageAnalysis},
+		{"Include Optimized", fieldIncludeOptimized},
+		{"Pricing Model", fieldPricingModel},
 		{"Instance Types", fieldInstanceTypes},
+		{"CUR File", fieldCURFile},
+		{"Price Overrides", fieldPricingOverrides},
+		{"Currency", fieldCurrency},
+		{"Exchange Rates", fieldExchangeRates},
+		{"Accounts / OU", fieldAccounts},
+		{"Role Name", fieldRoleName},
 	}
 
 	for _, f := range fields {
@@ -331,6 +552,14 @@ func (m ConfigModel) View() string {
 			value = m.renderCycleSelector(statOptions, m.statIndex, focused)
 		} else if f.index == fieldPreferNewGen {
 			value = m.renderCycleSelector(preferNewGenOptions, m.preferNewGenIndex, focused)
+		} else if f.index == fieldRICoverage {
+			value = m.renderCycleSelector(riCoverageOptions, m.riCoverageIndex, focused)
+		} else if f.index == fieldStorageAnalysis {
+			value = m.renderCycleSelector(storageAnalysisOptions, m.storageIndex, focused)
+		} else if f.index == fieldIncludeOptimized {
+			value = m.renderCycleSelector(includeOptimizedOptions, m.optimizedIndex, focused)
+		} else if f.index == fieldPricingModel {
+			value = m.renderWindowedSelector(pricingModelOptions, m.pricingModelIndex, focused)
 		} else {
 			value = m.inputs[f.index].View()
 		}
@@ -396,6 +625,13 @@ func (m ConfigModel) renderCycleSelector(options []string, selectedIndex int, fo
 	return prefix + strings.Join(parts, " ") + suffix
 }
 
+// renderWindowedSelector renders a cycling selector that only shows the selected
+// option and its position, for option lists too long to fit on one line.
+func (m ConfigModel) renderWindowedSelector(options []string, selectedIndex int, focused bool) string {
+	position := blurredInputStyle.Render(fmt.Sprintf(" (%d/%d)", selectedIndex+1, len(options)))
+	return m.renderCycleSelector(options[selectedIndex:selectedIndex+1], 0, focused) + position
+}
+
 // GetValues returns the current configuration values from the form.
 func (m ConfigModel) GetValues() (ConfigValues, error) {
 	period, err := strconv.Atoi(m.inputs[fieldPeriod].Value())
@@ -434,21 +670,47 @@ func (m ConfigModel) GetValues() (ConfigValues, error) {
 		}
 	}
 
+	minConfidence, err := strconv.ParseFloat(m.inputs[fieldMinConfidence].Value(), 64)
+	if err != nil || minConfidence < 0 || minConfidence > 100 {
+		if m.inputs[fieldMinConfidence].Value() == "" {
+			minConfidence = 0
+		} else {
+			return ConfigValues{}, fmt.Errorf("invalid minimum confidence (0-100): %s", m.inputs[fieldMinConfidence].Value())
+		}
+	}
+
+	if m.inputs[fieldAccounts].Value() != "" && m.inputs[fieldRoleName].Value() == "" {
+		return ConfigValues{}, fmt.Errorf("a role name is required to analyze other accounts")
+	}
+
 	instanceTypesURL := m.inputs[fieldInstanceTypes].Value()
 	if instanceTypesURL == "" {
 		instanceTypesURL = m.defaults.InstanceTypesURL
 	}
 
 	return ConfigValues{
-		Profile:          m.inputs[fieldProfile].Value(),
-		Region:           m.inputs[fieldRegion].Value(),
-		Tags:             m.inputs[fieldTags].Value(),
-		Period:           period,
-		CPUUpsize:        cpuUpsize,
-		CPUDownsize:      cpuDownsize,
-		MemUpsize:        memUpsize,
-		Stat:             statOptions[m.statIndex],
-		PreferNewGen:     m.preferNewGenIndex == 1,
-		InstanceTypesURL: instanceTypesURL,
+		Profile:              m.inputs[fieldProfile].Value(),
+		Region:               m.inputs[fieldRegion].Value(),
+		Tags:                 m.inputs[fieldTags].Value(),
+		Period:               period,
+		CPUUpsize:            cpuUpsize,
+		CPUDownsize:          cpuDownsize,
+		MemUpsize:            memUpsize,
+		MinConfidence:        minConfidence,
+		MinWindowDays:        m.defaults.MinWindowDays,
+		IdleThresholds:       m.defaults.IdleThresholds,
+		Stat:                 statOptions[m.statIndex],
+		PreferNewGen:         m.preferNewGenIndex == 1,
+		RICoverage:           m.riCoverageIndex == 1,
+		StorageAnalysis:      m.storageIndex == 1,
+		IncludeOptimized:     m.optimizedIndex == 1,
+		PricingModel:         pricingModelOptions[m.pricingModelIndex],
+		InstanceTypesURL:     instanceTypesURL,
+		CURFile:              m.inputs[fieldCURFile].Value(),
+		PricingOverridesFile: m.inputs[fieldPricingOverrides].Value(),
+		Currency:             m.inputs[fieldCurrency].Value(),
+		ExchangeRatesFile:    m.inputs[fieldExchangeRates].Value(),
+		Accounts:             m.inputs[fieldAccounts].Value(),
+		RoleName:             m.inputs[fieldRoleName].Value(),
 	}, nil
 }
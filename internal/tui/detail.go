@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -19,6 +20,7 @@ type DetailModel struct {
 	width          int
 	height         int
 	exportStatus   string
+	showTrace      bool
 }
 
 func NewDetailModel(rec *types.Recommendation, width, height int) DetailModel {
@@ -51,6 +53,13 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 			m.viewport.SetContent(m.renderContent())
 		}
 		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "t" && m.recommendation != nil && len(m.recommendation.DecisionTrace) > 0 {
+			m.showTrace = !m.showTrace
+			m.viewport.SetContent(m.renderContent())
+			return m, nil
+		}
 	}
 
 	var cmd tea.Cmd
@@ -81,7 +90,11 @@ func (m DetailModel) View() string {
 	}
 
 	scrollPct := fmt.Sprintf(" %d%%", int(m.viewport.ScrollPercent()*100))
-	help := helpStyle.Render("esc/b: back to list  p: export PNG  scroll: up/down/pgup/pgdn" + scrollPct)
+	traceHelp := ""
+	if m.recommendation != nil && len(m.recommendation.DecisionTrace) > 0 {
+		traceHelp = "t: decision trace  "
+	}
+	help := helpStyle.Render("esc/b: back to list  p: export PNG  " + traceHelp + "scroll: up/down/pgup/pgdn" + scrollPct)
 	b.WriteString(help)
 
 	return b.String()
@@ -107,8 +120,23 @@ func (m DetailModel) renderContent() string {
 	// Recommendation badge
 	sections = append(sections, m.renderRecommendationBadge())
 
+	// Measured utilization
+	if rec.Metrics != nil {
+		sections = append(sections, m.renderMetrics())
+	}
+
+	// Storage configuration cost model
+	if rec.StorageCost != nil {
+		sections = append(sections, m.renderStorageCost())
+	}
+
+	// Candidates considered, collapsed unless toggled
+	if len(rec.DecisionTrace) > 0 {
+		sections = append(sections, m.renderDecisionTrace())
+	}
+
 	// Instance comparison (current vs recommended)
-	if rec.Recommendation != types.Terminate && rec.CurrentInstanceProperties != nil && rec.TargetInstanceProperties != nil {
+	if !rec.Recommendation.RemovesInstance() && rec.CurrentInstanceProperties != nil && rec.TargetInstanceProperties != nil {
 		sections = append(sections, m.renderComparison())
 	}
 
@@ -136,6 +164,9 @@ func (m DetailModel) renderInstanceInfo() string {
 	if rec.DBInstanceArn != nil {
 		addRow("ARN:", *rec.DBInstanceArn)
 	}
+	if rec.AccountId != "" {
+		addRow("Account:", rec.AccountId)
+	}
 	if rec.AvailabilityZone != nil {
 		addRow("Availability Zone:", *rec.AvailabilityZone)
 	}
@@ -152,6 +183,9 @@ func (m DetailModel) renderInstanceInfo() string {
 	if rec.DBClusterIdentifier != nil {
 		addRow("Cluster:", *rec.DBClusterIdentifier)
 	}
+	if rec.StorageType != nil {
+		addRow("Storage:", "Aurora "+types.StorageTypeLabel(*rec.StorageType))
+	}
 
 	// Tags
 	if len(rec.Tags) > 0 {
@@ -180,6 +214,16 @@ func (m DetailModel) renderRecommendationBadge() string {
 		badge = badgeDownscale.Render(" DOWNSCALE ")
 	case types.Terminate:
 		badge = badgeTerminate.Render(" TERMINATE ")
+	case types.StorageConfiguration:
+		badge = badgeStorage.Render(" STORAGE ")
+	case types.Optimized:
+		badge = badgeOptimized.Render(" OPTIMIZED ")
+	case types.Skipped:
+		badge = badgeSkipped.Render(" SKIPPED ")
+	case types.Unavailable:
+		badge = badgeSkipped.Render(" UNAVAILABLE ")
+	case types.LikelyIdle:
+		badge = badgeTerminate.Render(" LIKELY IDLE ")
 	}
 
 	reason := lipgloss.NewStyle().Foreground(dimTextColor).Render("  " + string(rec.Reason))
@@ -204,12 +248,88 @@ func (m DetailModel) renderRecommendationBadge() string {
 	if rec.MonthlyApproximatePriceDiff != nil {
 		diff := *rec.MonthlyApproximatePriceDiff
 		if diff > 0 {
-			costInfo = costIncreaseStyle.Render(fmt.Sprintf("  Monthly impact: +$%.2f/mo (+$%.2f/yr)", diff, diff*12))
+			costInfo = costIncreaseStyle.Render(fmt.Sprintf("  Monthly impact: +%s/mo (+%s/yr)", rec.Currency.Format(diff), rec.Currency.Format(diff*12)))
+		} else {
+			costInfo = savingsStyle.Render(fmt.Sprintf("  Monthly savings: %s/mo (%s/yr)", rec.Currency.Format(diff*-1), rec.Currency.Format(diff*-12)))
+		}
+	}
+
+	if rec.EffectiveMonthlyPriceDiff != nil {
+		diff := *rec.EffectiveMonthlyPriceDiff
+		coverage := ""
+		if rec.ReservedCoverage != nil {
+			coverage = fmt.Sprintf(", %.0f%% of family usage reserved", *rec.ReservedCoverage*100)
+		}
+		if diff > 0 {
+			costInfo += costIncreaseStyle.Render(fmt.Sprintf("  With RI coverage: +%s/mo%s", rec.Currency.Format(diff), coverage))
 		} else {
-			costInfo = savingsStyle.Render(fmt.Sprintf("  Monthly savings: $%.2f/mo ($%.2f/yr)", diff*-1, diff*-12))
+			costInfo += savingsStyle.Render(fmt.Sprintf("  With RI coverage: %s/mo%s", rec.Currency.Format(diff*-1), coverage))
 		}
 	}
 
+	curNote := ""
+	if rec.CURDiscountRatio != nil {
+		list := ""
+		if rec.ListMonthlyPriceDiff != nil {
+			list = fmt.Sprintf(", list price diff: %s/mo", rec.Currency.Format(*rec.ListMonthlyPriceDiff))
+		}
+		rate := ""
+		if rec.CURHourlyRate != nil {
+			rate = fmt.Sprintf("effective rate %s/hr, ", rec.Currency.FormatRate(*rec.CURHourlyRate))
+		}
+		curNote = "\n  " + lipgloss.NewStyle().Foreground(dimTextColor).Italic(true).Render(
+			fmt.Sprintf("Calibrated against CUR: %s%.0f%% of on-demand%s", rate, *rec.CURDiscountRatio*100, list))
+	}
+
+	riWarning := ""
+	if rec.ReservedInstanceWarning != "" {
+		riWarning = "\n  " + lipgloss.NewStyle().Foreground(warningColor).Render(
+			"Stranded reservations: "+rec.ReservedInstanceWarning)
+	}
+
+	skipNote := ""
+	if rec.SkipReason != "" {
+		skipNote = "\n  " + lipgloss.NewStyle().Foreground(warningColor).Render("Skipped: "+rec.SkipReason)
+	}
+	if rec.Recommendation == types.Unavailable && rec.DBInstanceStatus != nil {
+		skipNote = "\n  " + lipgloss.NewStyle().Foreground(warningColor).Render("Status: "+*rec.DBInstanceStatus)
+	}
+
+	idleNote := ""
+	if len(rec.IdleEvidence) > 0 {
+		var signals []string
+		for _, signal := range rec.IdleEvidence {
+			signals = append(signals, fmt.Sprintf("%s %.2f (≤ %g)", signal.Metric, signal.Value, signal.Threshold))
+		}
+		idleNote = "\n  " + lipgloss.NewStyle().Foreground(warningColor).Render("Idle evidence: "+strings.Join(signals, ", "))
+	}
+
+	pendingNote := ""
+	if rec.RunningInstanceClass != nil && rec.DBInstanceClass != nil {
+		pendingNote = "\n  " + lipgloss.NewStyle().Foreground(dimTextColor).Render(
+			fmt.Sprintf("Analyzed against the pending class %s (currently %s)", *rec.DBInstanceClass, *rec.RunningInstanceClass))
+	}
+	if rec.RunningEngineVersion != nil && rec.EngineVersion != nil {
+		pendingNote += "\n  " + lipgloss.NewStyle().Foreground(dimTextColor).Render(
+			fmt.Sprintf("Analyzed against the pending engine version %s (currently %s)", *rec.EngineVersion, *rec.RunningEngineVersion))
+	}
+
+	previousGenNote := ""
+	if rec.PreviousGeneration {
+		note := "Previous-generation instance class"
+		if rec.EndOfLife != "" {
+			note += ", end of support " + rec.EndOfLife
+		}
+		previousGenNote = "\n  " + lipgloss.NewStyle().Foreground(warningColor).Render(note)
+	}
+
+	blockedNote := ""
+	if rec.BlockedGenerationUpgrade != nil {
+		blocked := rec.BlockedGenerationUpgrade
+		blockedNote = "\n  " + lipgloss.NewStyle().Foreground(warningColor).Render(
+			fmt.Sprintf("Engine upgrade needed: %s (saves another %s/mo)", blocked, rec.Currency.Format(blocked.MonthlySavings)))
+	}
+
 	connWarning := ""
 	if rec.MaxConnectionsAdjustRequired {
 		peakStr := ""
@@ -230,7 +350,122 @@ func (m DetailModel) renderRecommendationBadge() string {
 			"Adjusted for cluster homogeneity")
 	}
 
-	return "  " + badge + reason + metricInfo + projCPUInfo + "\n" + "  " + costInfo + connWarning + clusterNote
+	return "  " + badge + reason + metricInfo + projCPUInfo + "\n" + "  " + costInfo + skipNote + idleNote + pendingNote + curNote + riWarning + previousGenNote + blockedNote + connWarning + clusterNote
+}
+
+// renderMetrics shows the utilization measured over the lookback period and the
+// confidence it gives the recommendation.
+func (m DetailModel) renderMetrics() string {
+	metrics := m.recommendation.Metrics
+
+	var rows []string
+	addRow := func(label, value string) {
+		rows = append(rows, detailLabelStyle.Render(label)+detailValueStyle.Render(value))
+	}
+
+	addRow("Statistic:", fmt.Sprintf("%s over %d data points in %.1f days", metrics.Statistic, metrics.DataPoints, metrics.WindowDays))
+	if metrics.ClassChangedAt != nil {
+		value := fmt.Sprintf("metrics since the class change on %s (%s)", metrics.ClassChangedAt.UTC().Format("2006-01-02"), metrics.ClassChangeSource)
+		if m.recommendation.ShortMetricsWindow {
+			value = lowConfidenceStyle.Render(value + " — short window")
+		}
+		addRow("Window:", value)
+	}
+	if metrics.CPU != nil {
+		addRow("CPU:", fmt.Sprintf("%.1f%%", *metrics.CPU))
+	}
+	if metrics.FreeableMemoryGiB != nil {
+		value := fmt.Sprintf("%.2f GiB", *metrics.FreeableMemoryGiB)
+		if metrics.FreeableMemoryPercent != nil {
+			value += fmt.Sprintf(" (%.1f%% of memory)", *metrics.FreeableMemoryPercent)
+		}
+		addRow("Freeable memory:", value)
+	}
+	if metrics.ReadThroughput != nil && metrics.WriteThroughput != nil {
+		value := fmt.Sprintf("read %.1f KB/s, write %.1f KB/s", *metrics.ReadThroughput/1024, *metrics.WriteThroughput/1024)
+		if metrics.BandwidthPercent != nil {
+			value += fmt.Sprintf(" (%.1f%% of max bandwidth)", *metrics.BandwidthPercent)
+		}
+		addRow("Throughput:", value)
+	}
+	if metrics.PeakConnections != nil {
+		value := fmt.Sprintf("%.0f peak", *metrics.PeakConnections)
+		if metrics.ConnectionsPercent != nil && metrics.MaxConnections != nil {
+			value += fmt.Sprintf(" (%.1f%% of max_connections %d)", *metrics.ConnectionsPercent, *metrics.MaxConnections)
+		}
+		addRow("Connections:", value)
+	}
+	if rec := m.recommendation; rec.Confidence != nil {
+		value := fmt.Sprintf("%.0f/100", *rec.Confidence)
+		if *rec.Confidence < 50 {
+			value = lowConfidenceStyle.Render(value)
+		}
+		addRow("Confidence:", value)
+		for _, reason := range rec.ConfidenceReasons {
+			rows = append(rows, detailLabelStyle.Render("")+lipgloss.NewStyle().Foreground(dimTextColor).Render("- "+reason))
+		}
+	}
+
+	return detailBoxStyle.Render(strings.Join(rows, "\n"))
+}
+
+// renderDecisionTrace shows the candidate classes considered for the
+// recommendation, or a one-line header when collapsed.
+func (m DetailModel) renderDecisionTrace() string {
+	trace := m.recommendation.DecisionTrace
+
+	if !m.showTrace {
+		return lipgloss.NewStyle().Foreground(dimTextColor).Render(
+			fmt.Sprintf("  ▸ Decision trace (%d candidates) — press t to expand", len(trace)))
+	}
+
+	passStyle := lipgloss.NewStyle().Foreground(successColor)
+	failStyle := lipgloss.NewStyle().Foreground(dangerColor)
+	dimStyle := lipgloss.NewStyle().Foreground(dimTextColor)
+
+	rows := []string{chartTitleStyle.Render(fmt.Sprintf("▾ Decision trace (%d candidates)", len(trace)))}
+	for _, step := range trace {
+		mark := failStyle.Render("✗")
+		if step.Accepted {
+			mark = passStyle.Render("✓")
+		}
+		rows = append(rows, fmt.Sprintf("%s %s %s  %s",
+			mark, dimStyle.Render(string(step.Stage)), detailValueStyle.Render(step.Candidate), dimStyle.Render(step.Reason)))
+		for _, check := range step.Checks {
+			checkStyle := failStyle
+			if check.Passed {
+				checkStyle = passStyle
+			}
+			line := "    " + checkStyle.Render(check.Name)
+			if check.Value != "" {
+				line += dimStyle.Render(": " + check.Value)
+			}
+			rows = append(rows, line)
+		}
+	}
+
+	return detailBoxStyle.Render(strings.Join(rows, "\n"))
+}
+
+func (m DetailModel) renderStorageCost() string {
+	rec := m.recommendation
+	cost := rec.StorageCost
+
+	var rows []string
+	addRow := func(label, value string) {
+		rows = append(rows, detailLabelStyle.Render(label)+detailValueStyle.Render(value))
+	}
+	formatCost := func(c types.StorageConfigurationCost) string {
+		cur := rec.Currency
+		return fmt.Sprintf("%s/mo (instances %s, storage %s, I/O %s)", cur.Format(c.Total), cur.Format(c.Instances), cur.Format(c.Storage), cur.Format(c.IO))
+	}
+
+	addRow("Volume:", fmt.Sprintf("%.1f GiB", cost.VolumeGiB))
+	addRow("I/O requests:", fmt.Sprintf("%.1fM/mo", cost.MonthlyIORequests/1e6))
+	addRow("Standard:", formatCost(cost.Standard))
+	addRow("I/O-Optimized:", formatCost(cost.IOOptimized))
+
+	return detailBoxStyle.Render(strings.Join(rows, "\n"))
 }
 
 func regionFromAZ(az *string) string {
@@ -281,7 +516,7 @@ func (m DetailModel) renderComparison() string {
 	currentRows = append(currentRows, lipgloss.NewStyle().Bold(true).Foreground(textColor).Render(currentName))
 	currentRows = append(currentRows, "")
 	if current != nil {
-		currentPrice := current.GetPrice(region)
+		currentPrice := rec.Currency.Convert(current.GetInstancePrice(region, rec.PricingModel, aws.ToString(rec.StorageType)))
 		currentRows = append(currentRows, fmt.Sprintf("vCPU:       %d", current.Vcpu))
 		currentRows = append(currentRows, fmt.Sprintf("Memory:     %d GB", current.Mem))
 		if current.MaxBandwidth != nil {
@@ -290,8 +525,8 @@ func (m DetailModel) renderComparison() string {
 		if current.MaxConnections != nil {
 			currentRows = append(currentRows, fmt.Sprintf("Max Conns:  %d", *current.MaxConnections))
 		}
-		currentRows = append(currentRows, fmt.Sprintf("Price/hr:   $%.4f", currentPrice))
-		currentRows = append(currentRows, fmt.Sprintf("Price/mo:   $%.2f", currentPrice*730))
+		currentRows = append(currentRows, fmt.Sprintf("Price/hr:   %s", rec.Currency.FormatRate(currentPrice)))
+		currentRows = append(currentRows, fmt.Sprintf("Price/mo:   %s", rec.Currency.Format(currentPrice*730)))
 	}
 
 	// Target instance card
@@ -299,7 +534,7 @@ func (m DetailModel) renderComparison() string {
 	targetRows = append(targetRows, lipgloss.NewStyle().Bold(true).Foreground(textColor).Render(targetName))
 	targetRows = append(targetRows, "")
 	if target != nil {
-		targetPrice := target.GetPrice(region)
+		targetPrice := rec.Currency.Convert(target.GetInstancePrice(region, rec.PricingModel, aws.ToString(rec.StorageType)))
 		targetRows = append(targetRows, renderComparisonValue("vCPU", current.Vcpu, target.Vcpu))
 		targetRows = append(targetRows, renderComparisonMem("Memory", current.Mem, target.Mem))
 		if target.MaxBandwidth != nil {
@@ -316,8 +551,8 @@ func (m DetailModel) renderComparison() string {
 			}
 			targetRows = append(targetRows, renderComparisonConns("Max Conns", currentConns, *target.MaxConnections))
 		}
-		targetRows = append(targetRows, fmt.Sprintf("Price/hr:   $%.4f", targetPrice))
-		targetRows = append(targetRows, fmt.Sprintf("Price/mo:   $%.2f", targetPrice*730))
+		targetRows = append(targetRows, fmt.Sprintf("Price/hr:   %s", rec.Currency.FormatRate(targetPrice)))
+		targetRows = append(targetRows, fmt.Sprintf("Price/mo:   %s", rec.Currency.Format(targetPrice*730)))
 	}
 
 	currentCardStyle := currentInstanceStyle.Width(cardWidth)
@@ -396,7 +631,7 @@ func (m DetailModel) renderCharts() string {
 		caption := formatDateRange(metric)
 
 		// Show overlaid projected CPU when we have a scaling recommendation with different vCPU counts
-		canProject := rec.Recommendation != types.Terminate &&
+		canProject := !rec.Recommendation.RemovesInstance() &&
 			rec.CurrentInstanceProperties != nil &&
 			rec.TargetInstanceProperties != nil &&
 			rec.CurrentInstanceProperties.Vcpu != rec.TargetInstanceProperties.Vcpu
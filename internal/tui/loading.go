@@ -20,6 +20,7 @@ type AnalysisDoneMsg struct {
 	Err             error
 	Recommendations []types.Recommendation
 	Warnings        []string
+	TypesWarnings   []string // validation errors in the instance types that did not block analysis
 }
 
 type LoadingModel struct {
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/luneo7/rds-right-size/internal/currency"
 	rds "github.com/luneo7/rds-right-size/internal/rds-right-size"
 	"github.com/luneo7/rds-right-size/internal/rds-right-size/types"
 )
@@ -31,6 +32,7 @@ type columnLayout struct {
 	actionW     int
 	targetW     int
 	projCpuW    int
+	confW       int
 	reasonW     int
 	costW       int
 	showRegion  bool
@@ -39,12 +41,13 @@ type columnLayout struct {
 	showReason  bool
 	showCurrent bool
 	showProjCpu bool
+	showConf    bool
 }
 
 func computeColumns(width int) columnLayout {
 	if width >= breakpointWide {
-		// Wide: all columns including Region, Cluster, Engine, and Proj CPU
-		reasonW := width - 28 - 14 - 18 - 14 - 20 - 12 - 20 - 10 - 14 - 2
+		// Wide: all columns including Region, Cluster, Engine, Proj CPU, and Conf
+		reasonW := width - 28 - 14 - 18 - 14 - 20 - 12 - 20 - 10 - 6 - 14 - 2
 		if reasonW < 10 {
 			reasonW = 10
 		}
@@ -57,6 +60,7 @@ func computeColumns(width int) columnLayout {
 			actionW:     12,
 			targetW:     20,
 			projCpuW:    10,
+			confW:       6,
 			reasonW:     reasonW,
 			costW:       14,
 			showRegion:  true,
@@ -65,10 +69,11 @@ func computeColumns(width int) columnLayout {
 			showReason:  true,
 			showCurrent: true,
 			showProjCpu: true,
+			showConf:    true,
 		}
 	} else if width >= breakpointMedium {
 		// Medium-wide: show Region, hide Engine
-		reasonW := width - 28 - 14 - 18 - 20 - 12 - 20 - 10 - 14 - 2
+		reasonW := width - 28 - 14 - 18 - 20 - 12 - 20 - 10 - 6 - 14 - 2
 		if reasonW < 10 {
 			reasonW = 10
 		}
@@ -80,6 +85,7 @@ func computeColumns(width int) columnLayout {
 			actionW:     12,
 			targetW:     20,
 			projCpuW:    10,
+			confW:       6,
 			reasonW:     reasonW,
 			costW:       14,
 			showRegion:  true,
@@ -88,10 +94,11 @@ func computeColumns(width int) columnLayout {
 			showReason:  true,
 			showCurrent: true,
 			showProjCpu: true,
+			showConf:    true,
 		}
 	} else if width >= breakpointNarrow {
 		// Narrow: hide Region, Cluster, Engine, Proj CPU
-		reasonW := width - 26 - 18 - 12 - 18 - 14 - 2
+		reasonW := width - 26 - 18 - 12 - 18 - 6 - 14 - 2
 		if reasonW < 10 {
 			reasonW = 10
 		}
@@ -100,6 +107,7 @@ func computeColumns(width int) columnLayout {
 			currentW:    18,
 			actionW:     12,
 			targetW:     18,
+			confW:       6,
 			reasonW:     reasonW,
 			costW:       14,
 			showRegion:  false,
@@ -108,6 +116,7 @@ func computeColumns(width int) columnLayout {
 			showReason:  true,
 			showCurrent: true,
 			showProjCpu: false,
+			showConf:    true,
 		}
 	} else if width >= breakpointTight {
 		// Tight: Instance, Current, Action, Recommended, Cost
@@ -141,6 +150,7 @@ func computeColumns(width int) columnLayout {
 type ResultsModel struct {
 	recommendations []types.Recommendation
 	warnings        []string
+	typesWarnings   []string
 	cursor          int
 	scrollOffset    int
 	width           int
@@ -224,10 +234,33 @@ func (m ResultsModel) visibleRows() int {
 	if regionCount > 1 {
 		reserved += regionCount
 	}
+	// Add extra lines for per-account breakdown in multi-account mode
+	_, accounts := rds.CalculateAccountCostBreakdown(m.recommendations)
+	reserved += len(accounts)
 	// Add extra line for skipped instances warning
 	if len(m.warnings) > 0 {
 		reserved++
 	}
+	// Add extra lines for reserved instance coverage
+	if rds.CalculateCostBreakdown(m.recommendations).HasEffective {
+		reserved += 1 + len(rds.ReservedInstanceWarnings(m.recommendations))
+	}
+	// Add extra lines for engine upgrades that unlock savings
+	if upgrades := rds.EngineUpgrades(m.recommendations); len(upgrades) > 0 {
+		reserved += 1 + len(upgrades)
+	}
+	// Add extra line for the instance types provenance
+	if len(m.recommendations) > 0 && m.recommendations[0].InstanceTypesProvenance != nil {
+		reserved++
+	}
+	// Add extra line for instance types validation errors
+	if len(m.typesWarnings) > 0 {
+		reserved++
+	}
+	// Add extra line for recommendations priced on demand under a reserved model
+	if rds.OnDemandPriceFallbackCount(m.recommendations) > 0 {
+		reserved++
+	}
 	available := m.height - reserved
 	if available < 3 {
 		available = 3
@@ -313,6 +346,11 @@ func (m ResultsModel) renderSummary() string {
 	upscale := 0
 	downscale := 0
 	terminate := 0
+	storage := 0
+	optimized := 0
+	skipped := 0
+	unavailable := 0
+	idle := 0
 
 	for _, rec := range m.recommendations {
 		switch rec.Recommendation {
@@ -322,6 +360,16 @@ func (m ResultsModel) renderSummary() string {
 			downscale++
 		case types.Terminate:
 			terminate++
+		case types.StorageConfiguration:
+			storage++
+		case types.Optimized:
+			optimized++
+		case types.Skipped:
+			skipped++
+		case types.Unavailable:
+			unavailable++
+		case types.LikelyIdle:
+			idle++
 		}
 	}
 
@@ -331,27 +379,69 @@ func (m ResultsModel) renderSummary() string {
 	counts += upscaleStyle.Render(fmt.Sprintf("Upscale: %d", upscale)) + "  |  "
 	counts += downscaleStyle.Render(fmt.Sprintf("Downscale: %d", downscale)) + "  |  "
 	counts += terminateStyle.Render(fmt.Sprintf("Terminate: %d", terminate))
+	if idle > 0 {
+		counts += "  |  " + terminateStyle.Render(fmt.Sprintf("Likely idle: %d", idle))
+	}
+	if storage > 0 {
+		counts += "  |  " + storageStyle.Render(fmt.Sprintf("Storage: %d", storage))
+	}
+	if optimized > 0 || skipped > 0 {
+		counts += "  |  " + optimizedStyle.Render(fmt.Sprintf("Optimized: %d", optimized))
+		counts += "  |  " + skippedStyle.Render(fmt.Sprintf("Skipped: %d", skipped))
+	}
+	if unavailable > 0 {
+		counts += "  |  " + skippedStyle.Render(fmt.Sprintf("Unavailable: %d", unavailable))
+	}
+
+	var cur *currency.Currency
+	if len(m.recommendations) > 0 {
+		cur = m.recommendations[0].Currency
+	}
 
 	formatCostLine := func(label string, monthly float64) string {
 		yearly := monthly * 12
 		if monthly > 0 {
-			return costIncreaseStyle.Render(fmt.Sprintf("  %s: +$%.2f/mo (+$%.2f/yr)", label, monthly, yearly))
+			return costIncreaseStyle.Render(fmt.Sprintf("  %s: +%s/mo (+%s/yr)", label, cur.Format(monthly), cur.Format(yearly)))
 		} else if monthly < 0 {
 			savings := monthly * -1
 			yearlySavings := yearly * -1
-			return savingsStyle.Render(fmt.Sprintf("  %s: $%.2f/mo ($%.2f/yr)", label, savings, yearlySavings))
+			return savingsStyle.Render(fmt.Sprintf("  %s: %s/mo (%s/yr)", label, cur.Format(savings), cur.Format(yearlySavings)))
 		}
 		return lipgloss.NewStyle().Foreground(dimTextColor).Render(fmt.Sprintf("  %s: no cost impact", label))
 	}
 
 	var costLines string
-	if cb.HasTerminations && cb.ScalingMonthly != cb.TotalMonthly {
-		costLines = formatCostLine("Savings (scaling)", cb.ScalingMonthly) + "\n" +
-			formatCostLine("Savings (w/ terminate)", cb.TotalMonthly)
+	if (cb.HasTerminations || cb.HasStorage) && cb.ScalingMonthly != cb.TotalMonthly {
+		costLines = formatCostLine("Savings (scaling)", cb.ScalingMonthly)
+		if cb.HasStorage {
+			costLines += "\n" + formatCostLine("Savings (storage)", cb.StorageMonthly)
+		}
+		totalLabel := "Savings (total)"
+		if cb.HasTerminations {
+			totalLabel = "Savings (w/ terminate)"
+		}
+		costLines += "\n" + formatCostLine(totalLabel, cb.TotalMonthly)
 	} else {
 		costLines = formatCostLine("Savings", cb.TotalMonthly)
 	}
 
+	// Effective savings after reserved instance coverage
+	if cb.HasEffective {
+		costLines += "\n" + formatCostLine("Savings (w/ RI coverage)", cb.EffectiveMonthly)
+		for _, warning := range rds.ReservedInstanceWarnings(m.recommendations) {
+			costLines += "\n" + lipgloss.NewStyle().Foreground(warningColor).Render("  "+warning)
+		}
+	}
+
+	// Engine version upgrades that would allow newer-generation targets
+	if upgrades := rds.EngineUpgrades(m.recommendations); len(upgrades) > 0 {
+		costLines += "\n" + lipgloss.NewStyle().Foreground(warningColor).Render("  Engine upgrades that unlock savings:")
+		for _, u := range upgrades {
+			costLines += "\n" + savingsStyle.Render(fmt.Sprintf("    %s %s -> %s: %d instance(s), %s/mo (%s/yr)",
+				u.Engine, u.EngineVersion, u.RequiredEngineVersion, u.Instances, cur.Format(u.MonthlySavings), cur.Format(u.MonthlySavings*12)))
+		}
+	}
+
 	// Per-region breakdown when multiple regions are present
 	regionalCB, regions := rds.CalculateRegionalCostBreakdown(m.recommendations)
 	if len(regions) > 1 {
@@ -361,12 +451,43 @@ func (m ResultsModel) renderSummary() string {
 		}
 	}
 
+	// Per-account breakdown in multi-account mode
+	accountCB, accounts := rds.CalculateAccountCostBreakdown(m.recommendations)
+	for _, account := range accounts {
+		costLines += "\n" + formatCostLine("  Account "+account, accountCB[account].TotalMonthly)
+	}
+
 	// Skipped instances warning
 	if len(m.warnings) > 0 {
 		warnText := fmt.Sprintf("  %d instance(s) skipped (missing CloudWatch metrics)", len(m.warnings))
 		costLines += "\n" + lipgloss.NewStyle().Foreground(warningColor).Render(warnText)
 	}
 
+	// Instance types validation errors that did not block analysis
+	if len(m.typesWarnings) > 0 {
+		warnText := "  Instance types: " + m.typesWarnings[0]
+		if len(m.typesWarnings) > 1 {
+			warnText += " (run validate-types for all issues)"
+		}
+		costLines += "\n" + lipgloss.NewStyle().Foreground(warningColor).Render(warnText)
+	}
+
+	if len(m.recommendations) > 0 && m.recommendations[0].PricingModel != "" && m.recommendations[0].PricingModel != types.OnDemand {
+		model := m.recommendations[0].PricingModel
+		counts += lipgloss.NewStyle().Foreground(dimTextColor).Render("  |  Pricing: " + string(model))
+	}
+	if fallback := rds.OnDemandPriceFallbackCount(m.recommendations); fallback > 0 {
+		warnText := fmt.Sprintf("  %d recommendation(s) use on-demand prices for classes without a %s rate", fallback, m.recommendations[0].PricingModel)
+		costLines += "\n" + lipgloss.NewStyle().Foreground(warningColor).Render(warnText)
+	}
+	if label := cur.Label(); label != "" {
+		counts += lipgloss.NewStyle().Foreground(dimTextColor).Render("  |  Currency: " + label)
+	}
+
+	if len(m.recommendations) > 0 && m.recommendations[0].InstanceTypesProvenance != nil {
+		costLines += "\n" + lipgloss.NewStyle().Foreground(dimTextColor).Render("  Instance types: "+m.recommendations[0].InstanceTypesProvenance.Summary())
+	}
+
 	content := counts + "\n" + costLines
 	return summaryBoxStyle.Render(content)
 }
@@ -393,6 +514,9 @@ func (m ResultsModel) renderHeader() string {
 	if layout.showProjCpu {
 		cols = append(cols, tableHeaderStyle.Width(layout.projCpuW).Render("Proj CPU"))
 	}
+	if layout.showConf {
+		cols = append(cols, tableHeaderStyle.Width(layout.confW).Render("Conf"))
+	}
 	if layout.showReason {
 		cols = append(cols, tableHeaderStyle.Width(layout.reasonW).Render("Reason"))
 	}
@@ -407,6 +531,8 @@ func (m ResultsModel) renderRow(rec types.Recommendation, selected bool) string
 	instanceID := ""
 	if rec.DBInstanceIdentifier != nil {
 		instanceID = *rec.DBInstanceIdentifier
+	} else if rec.Recommendation == types.StorageConfiguration {
+		instanceID = "(cluster)"
 	}
 	maxInstLen := layout.instanceW - cursorPrefixWidth
 	if maxInstLen < 4 {
@@ -460,18 +586,47 @@ func (m ResultsModel) renderRow(rec types.Recommendation, selected bool) string
 	case types.Terminate:
 		recType = "TERMINATE"
 		recStyle = terminateStyle
+	case types.StorageConfiguration:
+		recType = "STORAGE"
+		recStyle = storageStyle
+	case types.Optimized:
+		recType = "OPTIMIZED"
+		recStyle = optimizedStyle
+	case types.Skipped:
+		recType = "SKIPPED"
+		recStyle = skippedStyle
+	case types.Unavailable:
+		recType = "UNAVAILABLE"
+		recStyle = skippedStyle
+	case types.LikelyIdle:
+		recType = "LIKELY IDLE"
+		recStyle = terminateStyle
 	}
 
 	target := ""
 	if rec.RecommendedInstanceType != nil {
 		target = *rec.RecommendedInstanceType
 	}
+	if rec.RecommendedStorageType != nil {
+		if rec.StorageType != nil {
+			currentType = types.StorageTypeLabel(*rec.StorageType)
+		}
+		target = types.StorageTypeLabel(*rec.RecommendedStorageType)
+	}
 
 	projCpu := ""
 	if rec.ProjectedCPU != nil {
 		projCpu = fmt.Sprintf("%.1f%%", *rec.ProjectedCPU)
 	}
 
+	conf := ""
+	if rec.Confidence != nil {
+		conf = fmt.Sprintf("%.0f", *rec.Confidence)
+		if *rec.Confidence < 50 {
+			conf = lowConfidenceStyle.Render(conf)
+		}
+	}
+
 	reason := string(rec.Reason)
 	maxReasonLen := layout.reasonW - 2
 	if maxReasonLen > 0 && len(reason) > maxReasonLen {
@@ -481,9 +636,9 @@ func (m ResultsModel) renderRow(rec types.Recommendation, selected bool) string
 	costDiff := ""
 	if rec.MonthlyApproximatePriceDiff != nil {
 		if *rec.MonthlyApproximatePriceDiff > 0 {
-			costDiff = costIncreaseStyle.Render(fmt.Sprintf("+$%.2f", *rec.MonthlyApproximatePriceDiff))
+			costDiff = costIncreaseStyle.Render("+" + rec.Currency.Format(*rec.MonthlyApproximatePriceDiff))
 		} else {
-			costDiff = savingsStyle.Render(fmt.Sprintf("-$%.2f", *rec.MonthlyApproximatePriceDiff*-1))
+			costDiff = savingsStyle.Render(rec.Currency.Format(*rec.MonthlyApproximatePriceDiff))
 		}
 	}
 
@@ -511,6 +666,9 @@ func (m ResultsModel) renderRow(rec types.Recommendation, selected bool) string
 	if layout.showProjCpu {
 		cols = append(cols, baseStyle.Width(layout.projCpuW).Render(projCpu))
 	}
+	if layout.showConf {
+		cols = append(cols, baseStyle.Width(layout.confW).Render(conf))
+	}
 	if layout.showReason {
 		cols = append(cols, baseStyle.Width(layout.reasonW).Render(reason))
 	}
@@ -73,6 +73,18 @@ var (
 			Foreground(warningColor).
 			Bold(true)
 
+	storageStyle = lipgloss.NewStyle().
+			Foreground(secondaryColor).
+			Bold(true)
+
+	optimizedStyle = lipgloss.NewStyle().
+			Foreground(textColor).
+			Bold(true)
+
+	skippedStyle = lipgloss.NewStyle().
+			Foreground(mutedColor).
+			Bold(true)
+
 	// Table styles
 	tableHeaderStyle = lipgloss.NewStyle().
 				Bold(true).
@@ -128,6 +140,9 @@ var (
 				Foreground(dangerColor).
 				Bold(true)
 
+	lowConfidenceStyle = lipgloss.NewStyle().
+				Foreground(warningColor)
+
 	// Help styles
 	helpStyle = lipgloss.NewStyle().
 			Foreground(mutedColor).
@@ -161,6 +176,24 @@ var (
 			Bold(true).
 			Padding(0, 1)
 
+	badgeStorage = lipgloss.NewStyle().
+			Foreground(textColor).
+			Background(secondaryColor).
+			Bold(true).
+			Padding(0, 1)
+
+	badgeOptimized = lipgloss.NewStyle().
+			Foreground(textColor).
+			Background(borderColor).
+			Bold(true).
+			Padding(0, 1)
+
+	badgeSkipped = lipgloss.NewStyle().
+			Foreground(textColor).
+			Background(mutedColor).
+			Bold(true).
+			Padding(0, 1)
+
 	// Comparison styles (current vs recommended)
 	currentInstanceStyle = lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
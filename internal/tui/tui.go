@@ -6,6 +6,9 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/luneo7/rds-right-size/internal/cache"
+	"github.com/luneo7/rds-right-size/internal/cur"
+	"github.com/luneo7/rds-right-size/internal/currency"
 	cwTypes "github.com/luneo7/rds-right-size/internal/cw/types"
 	"github.com/luneo7/rds-right-size/internal/export"
 	"github.com/luneo7/rds-right-size/internal/generator"
@@ -51,11 +54,13 @@ type Model struct {
 	statusChan     chan string        // receives generation status text from OnStatus callbacks
 	cancelAnalysis context.CancelFunc // cancels the running analysis goroutine
 	region         string             // AWS region from config, used for pricing lookups and exports
+	downloadCache  *cache.Cache       // on-disk cache for instance types and pricing downloads (may be nil)
 }
 
-func NewModel(defaults ConfigValues) Model {
+func NewModel(defaults ConfigValues, downloadCache *cache.Cache) Model {
 	return Model{
 		currentScreen: screenConfig,
+		downloadCache: downloadCache,
 		config:        NewConfigModel(defaults),
 		loading:       NewLoadingModel(0, 0),
 	}
@@ -216,6 +221,7 @@ func (m Model) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.currentScreen = screenResults
 		m.results = NewResultsModel(msg.Recommendations, m.width, m.height)
 		m.results.warnings = msg.Warnings
+		m.results.typesWarnings = msg.TypesWarnings
 		return m, nil
 	}
 
@@ -444,6 +450,7 @@ func (m Model) runGeneration(profile, region string, submit GenerateSubmitMsg, s
 			Region:        region,
 			TargetRegions: submit.TargetRegions,
 			Output:        submit.OutputFile,
+			Cache:         m.downloadCache,
 			OnStatus: func(status string) {
 				select {
 				case statusChan <- status:
@@ -468,9 +475,43 @@ func (m Model) runAnalysis(ctx context.Context, values ConfigValues) tea.Cmd {
 	return func() tea.Msg {
 		regions := util.SplitRegions(values.Region)
 		tags := util.ParseTags(values.Tags)
+		accountIds, orgUnit := util.SplitAccounts(values.Accounts)
+
+		var curCosts cur.Costs
+		if values.CURFile != "" {
+			var err error
+			curCosts, err = cur.Load(values.CURFile)
+			if err != nil {
+				close(progressChan)
+				return AnalysisDoneMsg{Err: err}
+			}
+		}
+
+		var pricingOverrides *types.PricingOverrides
+		if values.PricingOverridesFile != "" {
+			var err error
+			pricingOverrides, err = rds.LoadPricingOverrides(values.PricingOverridesFile)
+			if err != nil {
+				close(progressChan)
+				return AnalysisDoneMsg{Err: err}
+			}
+		}
+
+		if values.CURFile != "" {
+			if err := rds.CheckCURCalibration(types.PricingModel(values.PricingModel), pricingOverrides); err != nil {
+				close(progressChan)
+				return AnalysisDoneMsg{Err: err}
+			}
+		}
+
+		reportCurrency, err := currency.Load(values.Currency, values.ExchangeRatesFile)
+		if err != nil {
+			close(progressChan)
+			return AnalysisDoneMsg{Err: err}
+		}
 
 		// Single region (or no region specified) — existing behavior
-		if len(regions) <= 1 {
+		if len(regions) <= 1 && len(accountIds) == 0 && orgUnit == "" {
 			region := values.Region
 
 			var optFns []func(*config.LoadOptions) error
@@ -487,8 +528,15 @@ func (m Model) runAnalysis(ctx context.Context, values ConfigValues) tea.Cmd {
 				return AnalysisDoneMsg{Err: err}
 			}
 
+			instanceTypes, err := rds.LoadInstanceTypes(values.InstanceTypesURL, pricingOverrides, m.downloadCache)
+			if err != nil {
+				close(progressChan)
+				return AnalysisDoneMsg{Err: err}
+			}
+
 			analyzer := rds.NewRDSRightSize(
-				&values.InstanceTypesURL,
+				instanceTypes,
+				values.InstanceTypesURL,
 				&cfg,
 				values.Period,
 				tags,
@@ -498,11 +546,23 @@ func (m Model) runAnalysis(ctx context.Context, values ConfigValues) tea.Cmd {
 				cwTypes.StatName(values.Stat),
 				values.PreferNewGen,
 				region,
+				types.PricingModel(values.PricingModel),
+				pricingOverrides,
 			)
 
 			var warnings []string
 			opts := &rds.AnalysisOptions{
-				FetchTimeSeries: true,
+				FetchTimeSeries:  true,
+				ReservedCoverage: values.RICoverage,
+				StorageAnalysis:  values.StorageAnalysis,
+				IncludeOptimized: values.IncludeOptimized,
+				Explain:          true,
+				MinConfidence:    values.MinConfidence,
+				MinWindowDays:    values.MinWindowDays,
+				IdleThresholds:   values.IdleThresholds,
+				CURCosts:         curCosts,
+				Currency:         reportCurrency,
+				Cache:            m.downloadCache,
 				OnProgress: func(current int, total int, instanceId string) {
 					progressChan <- ProgressMsg{
 						Current:    current,
@@ -526,22 +586,38 @@ func (m Model) runAnalysis(ctx context.Context, values ConfigValues) tea.Cmd {
 				recommendations[i].Region = region
 			}
 
-			return AnalysisDoneMsg{Recommendations: recommendations, Warnings: warnings}
+			return AnalysisDoneMsg{Recommendations: recommendations, Warnings: warnings, TypesWarnings: instanceTypes.Warnings}
 		}
 
 		// Multi-region parallel analysis
+		var typesWarnings []string
 		allRecs, allWarnings, err := rds.AnalyzeMultiRegion(ctx, rds.MultiRegionOptions{
-			Regions:          regions,
-			Profile:          values.Profile,
-			InstanceTypesURL: values.InstanceTypesURL,
-			Period:           values.Period,
-			Tags:             tags,
-			CPUDownsize:      values.CPUDownsize,
-			CPUUpsize:        values.CPUUpsize,
-			MemUpsize:        values.MemUpsize,
-			Stat:             cwTypes.StatName(values.Stat),
-			PreferNewGen:     values.PreferNewGen,
-			FetchTimeSeries:  true,
+			Regions:            regions,
+			Profile:            values.Profile,
+			InstanceTypesURL:   values.InstanceTypesURL,
+			Period:             values.Period,
+			Tags:               tags,
+			CPUDownsize:        values.CPUDownsize,
+			CPUUpsize:          values.CPUUpsize,
+			MemUpsize:          values.MemUpsize,
+			Stat:               cwTypes.StatName(values.Stat),
+			PreferNewGen:       values.PreferNewGen,
+			PricingModel:       types.PricingModel(values.PricingModel),
+			PricingOverrides:   pricingOverrides,
+			FetchTimeSeries:    true,
+			ReservedCoverage:   values.RICoverage,
+			StorageAnalysis:    values.StorageAnalysis,
+			IncludeOptimized:   values.IncludeOptimized,
+			Explain:            true,
+			MinConfidence:      values.MinConfidence,
+			MinWindowDays:      values.MinWindowDays,
+			IdleThresholds:     values.IdleThresholds,
+			CURCosts:           curCosts,
+			Currency:           reportCurrency,
+			Cache:              m.downloadCache,
+			Accounts:           accountIds,
+			OrganizationalUnit: orgUnit,
+			RoleName:           values.RoleName,
 			OnProgress: func(current, total int, instanceLabel string) {
 				progressChan <- ProgressMsg{
 					Current:    current,
@@ -549,12 +625,15 @@ func (m Model) runAnalysis(ctx context.Context, values ConfigValues) tea.Cmd {
 					InstanceID: instanceLabel,
 				}
 			},
+			OnInstanceTypesWarning: func(msg string) {
+				typesWarnings = append(typesWarnings, msg)
+			},
 		})
 		close(progressChan)
 		if err != nil {
 			return AnalysisDoneMsg{Err: err}
 		}
-		return AnalysisDoneMsg{Recommendations: allRecs, Warnings: allWarnings}
+		return AnalysisDoneMsg{Recommendations: allRecs, Warnings: allWarnings, TypesWarnings: typesWarnings}
 	}
 }
 
@@ -581,9 +660,10 @@ func waitForStatus(ch chan string) tea.Cmd {
 	}
 }
 
-// Run starts the TUI application.
-func Run(defaults ConfigValues) error {
-	p := tea.NewProgram(NewModel(defaults), tea.WithAltScreen())
+// Run starts the TUI application. downloadCache, which may be nil, backs instance
+// types and pricing downloads.
+func Run(defaults ConfigValues, downloadCache *cache.Cache) error {
+	p := tea.NewProgram(NewModel(defaults, downloadCache), tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
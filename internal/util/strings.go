@@ -1,6 +1,10 @@
 package util
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // ParseTags parses a comma-separated "key=value" string into a map.
 // Entries that are empty or don't contain exactly one "=" are silently skipped.
@@ -21,12 +25,45 @@ func ParseTags(tags string) map[string]string {
 // SplitRegions splits a comma-separated region string into a slice,
 // trimming whitespace and filtering empty entries.
 func SplitRegions(s string) []string {
-	var regions []string
-	for _, r := range strings.Split(s, ",") {
-		r = strings.TrimSpace(r)
-		if r != "" {
-			regions = append(regions, r)
+	return SplitList(s)
+}
+
+// SplitList splits a comma-separated string into a slice,
+// trimming whitespace and filtering empty entries.
+func SplitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SplitInts splits a comma-separated list of integers, trimming whitespace and
+// filtering empty entries.
+func SplitInts(s string) ([]int, error) {
+	var values []int
+	for _, item := range SplitList(s) {
+		v, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", item)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// SplitAccounts splits a comma-separated list of account IDs that may include one
+// AWS Organizations OU or root ID ("ou-..." or "r-...").
+func SplitAccounts(s string) (accountIds []string, orgUnit string) {
+	for _, item := range SplitList(s) {
+		if strings.HasPrefix(item, "ou-") || strings.HasPrefix(item, "r-") {
+			orgUnit = item
+			continue
 		}
+		accountIds = append(accountIds, item)
 	}
-	return regions
+	return accountIds, orgUnit
 }
@@ -54,3 +54,27 @@ func ExtractVersionSegments(version string) []int {
 	}
 	return segments
 }
+
+// EngineMinorVersion reduces an engine version to its major/minor release, the
+// level at which instance class support changes. Aurora MySQL versions keep the
+// MySQL version and the Aurora major/minor, other versions the first two segments.
+//
+// Examples:
+//   - "8.0.mysql_aurora.3.04.0" → "8.0.mysql_aurora.3.04"
+//   - "15.4" → "15.4"
+//   - "9.6.22" → "9.6"
+func EngineMinorVersion(version string) string {
+	parts := strings.Split(version, ".")
+	keep := 2
+	for i, p := range parts {
+		if !strings.HasSuffix(p, "_aurora") {
+			continue
+		}
+		keep = i + 3
+		break
+	}
+	if len(parts) > keep {
+		parts = parts[:keep]
+	}
+	return strings.Join(parts, ".")
+}